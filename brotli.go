@@ -0,0 +1,468 @@
+package atomfeed
+
+import (
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// Brotli is the brotli compressed variant of a feed file.
+// The encoder is part of the package: it finds repetitions with a hash chain and
+// writes them with prefix codes fitted to the feed, without context modeling and
+// without the static dictionary of the brotli format.
+//  https://tools.ietf.org/html/rfc7932
+var Brotli = Variant{
+	Ext: ".br",
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return &brotliWriter{w: w}, nil
+	},
+}
+
+const (
+	brotliWindowBits   = 22
+	brotliWindowSize   = 1<<brotliWindowBits - 16 // largest backward distance
+	brotliMetaBlock    = 1 << 20                  // uncompressed bytes per meta-block
+	brotliMinMatch     = 4
+	brotliMaxMatch     = 1 << 16
+	brotliMaxChain     = 32 // candidates compared per position
+	brotliHashBits     = 15
+	brotliLiteralBits  = 8  // bits of a literal symbol within a simple prefix code
+	brotliCommandBits  = 10 // bits of an insert-and-copy length symbol
+	brotliDistanceBits = 6  // bits of a distance symbol
+)
+
+// brotliWriter compresses all data written to it, when it is closed.
+type brotliWriter struct {
+	w    io.Writer
+	data []byte
+}
+
+func (w *brotliWriter) Write(p []byte) (int, error) {
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+// Close writes the brotli stream of all written data.
+func (w *brotliWriter) Close() error {
+	b := &bitWriter{}
+	b.writeBits(1, 1) // WBITS = 17 + 5
+	b.writeBits(3, brotliWindowBits-17)
+	m := newMatcher(w.data)
+	for start := 0; start < len(w.data); start += brotliMetaBlock {
+		end := start + brotliMetaBlock
+		if end > len(w.data) {
+			end = len(w.data)
+		}
+		b.writeMetaBlock(w.data, start, end, m.commands(start, end))
+	}
+	b.writeBits(1, 1) // ISLAST
+	b.writeBits(1, 1) // ISLASTEMPTY
+	_, err := w.w.Write(b.bytes())
+	return err
+}
+
+// command inserts literals and copies data from a previous position.
+// The last command of a meta-block may only insert literals (copy is 0).
+type command struct {
+	insert, literals int // number and position of literals
+	copy, distance   int
+}
+
+// matcher finds the longest previous repetitions of data with a hash chain.
+type matcher struct {
+	data []byte
+	head []int32 // last position+1 of every hash
+	prev []int32 // previous position+1 with the same hash of every position
+}
+
+func newMatcher(data []byte) *matcher {
+	return &matcher{data: data, head: make([]int32, 1<<brotliHashBits), prev: make([]int32, len(data))}
+}
+
+func (m *matcher) hash(p int) uint32 {
+	return binary.LittleEndian.Uint32(m.data[p:]) * 0x1e35a7bd >> (32 - brotliHashBits)
+}
+
+func (m *matcher) insert(p int) {
+	h := m.hash(p)
+	m.prev[p] = m.head[h]
+	m.head[h] = int32(p + 1)
+}
+
+// commands returns the commands for data[start:end], which copy greedily the longest repetitions.
+func (m *matcher) commands(start, end int) []command {
+	commands := []command{}
+	literals := start
+	for p := start; p+brotliMinMatch <= end; {
+		length, distance := m.match(p, end)
+		if length < brotliMinMatch {
+			m.insert(p)
+			p++
+			continue
+		}
+		commands = append(commands, command{insert: p - literals, literals: literals, copy: length, distance: distance})
+		for last := p + length; p < last; p++ {
+			if p+brotliMinMatch <= end {
+				m.insert(p)
+			}
+		}
+		literals = p
+	}
+	if literals < end {
+		commands = append(commands, command{insert: end - literals, literals: literals})
+	}
+	return commands
+}
+
+// match returns the longest repetition of the data at p within data[:end].
+func (m *matcher) match(p, end int) (length, distance int) {
+	limit := end - p
+	if limit > brotliMaxMatch {
+		limit = brotliMaxMatch
+	}
+	candidate := int(m.head[m.hash(p)]) - 1
+	for chain := 0; candidate >= 0 && p-candidate <= brotliWindowSize && chain < brotliMaxChain; chain++ {
+		n := 0
+		for n < limit && m.data[candidate+n] == m.data[p+n] {
+			n++
+		}
+		if n > length {
+			length, distance = n, p-candidate
+			if n == limit {
+				break
+			}
+		}
+		candidate = int(m.prev[candidate]) - 1
+	}
+	return length, distance
+}
+
+// insertLengths and copyLengths are the smallest lengths and the number of extra bits of the length codes.
+var (
+	insertLengths = [24][2]int{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 1}, {8, 1}, {10, 2}, {14, 2}, {18, 3}, {26, 3},
+		{34, 4}, {50, 4}, {66, 5}, {98, 5}, {130, 6}, {194, 7}, {322, 8}, {578, 9}, {1090, 10}, {2114, 12}, {6210, 14}, {22594, 24}}
+	copyLengths = [24][2]int{{2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}, {7, 0}, {8, 0}, {9, 0}, {10, 1}, {12, 1}, {14, 2}, {18, 2},
+		{22, 3}, {30, 3}, {38, 4}, {54, 4}, {70, 5}, {102, 5}, {134, 6}, {198, 7}, {326, 8}, {582, 9}, {1094, 10}, {2118, 24}}
+)
+
+// lengthCode returns the code of length within codes.
+func lengthCode(codes *[24][2]int, length int) int {
+	code := 0
+	for code < len(codes)-1 && codes[code+1][0] <= length {
+		code++
+	}
+	return code
+}
+
+// commandSymbol returns the insert-and-copy length symbol with an explicit distance.
+func commandSymbol(insertCode, copyCode int) int {
+	cells := [3][3]int{{128, 192, 384}, {256, 320, 512}, {448, 576, 640}}
+	return cells[insertCode>>3][copyCode>>3] + (insertCode&7)<<3 + copyCode&7
+}
+
+// distanceSymbol returns the symbol and the extra bits of a distance without postfix bits and direct distance codes.
+func distanceSymbol(distance int) (symbol int, bits uint, extra int) {
+	d := distance + 3
+	msb := uint(0)
+	for d>>(msb+1) != 0 {
+		msb++
+	}
+	bits = msb - 1
+	high := (d >> bits) & 1
+	return 16 + int(bits-1)*2 + high, bits, d - (2+high)<<bits
+}
+
+// writeMetaBlock writes a compressed meta-block of data[start:end].
+func (b *bitWriter) writeMetaBlock(data []byte, start, end int, commands []command) {
+	literalCounts := make([]int, 256)
+	commandCounts := make([]int, 704)
+	distanceCounts := make([]int, 64)
+	type encoded struct {
+		symbol, insertCode, copyCode, distance int
+		distanceBits                           uint
+		distanceExtra                          int
+	}
+	encodedCommands := make([]encoded, len(commands))
+	for i, c := range commands {
+		for _, l := range data[c.literals : c.literals+c.insert] {
+			literalCounts[l]++
+		}
+		e := encoded{insertCode: lengthCode(&insertLengths, c.insert)}
+		if c.copy > 0 {
+			e.copyCode = lengthCode(&copyLengths, c.copy)
+			e.distance, e.distanceBits, e.distanceExtra = distanceSymbol(c.distance)
+			distanceCounts[e.distance]++
+		}
+		e.symbol = commandSymbol(e.insertCode, e.copyCode)
+		commandCounts[e.symbol]++
+		encodedCommands[i] = e
+	}
+
+	length := end - start
+	nibbles := uint(4)
+	for (length-1)>>(4*nibbles) != 0 {
+		nibbles++
+	}
+	b.writeBits(1, 0) // ISLAST
+	b.writeBits(2, uint64(nibbles-4))
+	b.writeBits(4*nibbles, uint64(length-1))
+	b.writeBits(1, 0) // ISUNCOMPRESSED
+	b.writeBits(1, 0) // NBLTYPESL = 1
+	b.writeBits(1, 0) // NBLTYPESI = 1
+	b.writeBits(1, 0) // NBLTYPESD = 1
+	b.writeBits(2, 0) // NPOSTFIX
+	b.writeBits(4, 0) // NDIRECT
+	b.writeBits(2, 0) // context mode LSB6
+	b.writeBits(1, 0) // NTREESL = 1
+	b.writeBits(1, 0) // NTREESD = 1
+	literals := b.writePrefixCode(literalCounts, brotliLiteralBits)
+	commandCode := b.writePrefixCode(commandCounts, brotliCommandBits)
+	distances := b.writePrefixCode(distanceCounts, brotliDistanceBits)
+
+	for i, c := range commands {
+		e := encodedCommands[i]
+		commandCode.write(b, e.symbol)
+		b.writeBits(uint(insertLengths[e.insertCode][1]), uint64(c.insert-insertLengths[e.insertCode][0]))
+		if c.copy > 0 {
+			b.writeBits(uint(copyLengths[e.copyCode][1]), uint64(c.copy-copyLengths[e.copyCode][0]))
+		}
+		for _, l := range data[c.literals : c.literals+c.insert] {
+			literals.write(b, int(l))
+		}
+		if c.copy > 0 {
+			distances.write(b, e.distance)
+			b.writeBits(e.distanceBits, uint64(e.distanceExtra))
+		}
+	}
+}
+
+// prefixCode is a canonical prefix code, whose codes are bit-reversed to be written starting with the least significant bit.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+}
+
+func (c prefixCode) write(b *bitWriter, symbol int) {
+	b.writeBits(uint(c.lengths[symbol]), uint64(c.codes[symbol]))
+}
+
+// codeLengthOrder is the order of the code length code lengths within a complex prefix code.
+var codeLengthOrder = [18]int{1, 2, 3, 4, 0, 5, 17, 6, 16, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// codeLengthCodes is the static code of the code length code lengths 0 to 5.
+var codeLengthCodes = [6]struct {
+	bits uint
+	code uint64
+}{{2, 0}, {4, 7}, {3, 3}, {2, 2}, {2, 1}, {4, 15}}
+
+// writePrefixCode writes the prefix code of the symbol counts and returns it.
+// Alphabets with a single used symbol get a simple prefix code, whose symbol takes no bits.
+func (b *bitWriter) writePrefixCode(counts []int, alphabetBits uint) prefixCode {
+	used := []int{}
+	for s, count := range counts {
+		if count > 0 {
+			used = append(used, s)
+		}
+	}
+	if len(used) <= 1 {
+		b.writeBits(2, 1) // simple prefix code
+		b.writeBits(2, 0) // NSYM - 1
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		b.writeBits(alphabetBits, uint64(symbol))
+		return prefixCode{lengths: make([]uint8, len(counts)), codes: make([]uint16, len(counts))}
+	}
+	code := newPrefixCode(counts, 15)
+
+	// code lengths up to the last used symbol; runs of zeros are repeated with code 17
+	type token struct {
+		symbol int
+		extra  uint64
+	}
+	tokens := []token{}
+	last := used[len(used)-1]
+	for s := 0; s <= last; {
+		if code.lengths[s] != 0 {
+			tokens = append(tokens, token{symbol: int(code.lengths[s])})
+			s++
+			continue
+		}
+		reps := 0
+		for s+reps <= last && code.lengths[s+reps] == 0 {
+			reps++
+		}
+		s += reps
+		if reps == 11 {
+			tokens = append(tokens, token{})
+			reps--
+		}
+		if reps < 3 {
+			for ; reps > 0; reps-- {
+				tokens = append(tokens, token{})
+			}
+			continue
+		}
+		// consecutive repeat codes multiply their repetitions: write them most significant first
+		first := len(tokens)
+		for reps -= 3; ; reps-- {
+			tokens = append(tokens, token{17, uint64(reps & 7)})
+			if reps >>= 3; reps == 0 {
+				break
+			}
+		}
+		for i, j := first, len(tokens)-1; i < j; i, j = i+1, j-1 {
+			tokens[i], tokens[j] = tokens[j], tokens[i]
+		}
+	}
+
+	tokenCounts := make([]int, 18)
+	for _, t := range tokens {
+		tokenCounts[t.symbol]++
+	}
+	lengths := newPrefixCode(tokenCounts, 5)
+	single := 0
+	for _, count := range tokenCounts {
+		if count > 0 {
+			single++
+		}
+	}
+	b.writeBits(2, 0) // HSKIP
+	space := 32
+	for _, s := range codeLengthOrder {
+		length := lengths.lengths[s]
+		b.writeBits(codeLengthCodes[length].bits, codeLengthCodes[length].code)
+		if length != 0 {
+			space -= 32 >> length
+		}
+		if single > 1 && space <= 0 { // a single code length takes no bits and all 18 lengths are written
+			break
+		}
+	}
+	for _, t := range tokens {
+		if single > 1 {
+			lengths.write(b, t.symbol)
+		}
+		if t.symbol == 17 {
+			b.writeBits(3, t.extra)
+		}
+	}
+	return code
+}
+
+// newPrefixCode returns the canonical prefix code of the symbol counts, whose codes are at most limit bits long.
+// A single used symbol gets a code of length 1.
+func newPrefixCode(counts []int, limit int) prefixCode {
+	lengths := huffmanLengths(counts, limit)
+	count := make([]int, limit+1)
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+	next := make([]int, limit+1)
+	for l, code := 1, 0; l <= limit; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	codes := make([]uint16, len(counts))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		code := next[l]
+		next[l]++
+		reversed := uint16(0)
+		for i := uint8(0); i < l; i++ {
+			reversed = reversed<<1 | uint16(code>>i&1)
+		}
+		codes[s] = reversed
+	}
+	return prefixCode{lengths: lengths, codes: codes}
+}
+
+// huffmanLengths returns the code lengths of a Huffman code of the symbol counts, which are at most limit.
+// Too long codes are avoided by raising small counts: the minimum count doubles until all codes fit.
+func huffmanLengths(counts []int, limit int) []uint8 {
+	lengths := make([]uint8, len(counts))
+	type node struct {
+		weight      int
+		left, right int // children of inner nodes, symbol of leaves within left
+	}
+	for minimum := 1; ; minimum *= 2 {
+		nodes := []node{}
+		for s, count := range counts {
+			if count > 0 {
+				if count < minimum {
+					count = minimum
+				}
+				nodes = append(nodes, node{weight: count, left: s})
+			}
+		}
+		if len(nodes) == 1 {
+			lengths[nodes[0].left] = 1
+		}
+		if len(nodes) <= 1 {
+			return lengths
+		}
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].weight < nodes[j].weight })
+		leaves := len(nodes)
+		leaf, inner := 0, leaves // fronts of the queues of leaves and inner nodes, both in ascending order
+		lightest := func() int {
+			if leaf < leaves && (inner >= len(nodes) || nodes[leaf].weight <= nodes[inner].weight) {
+				leaf++
+				return leaf - 1
+			}
+			inner++
+			return inner - 1
+		}
+		for i := 1; i < leaves; i++ {
+			left := lightest()
+			right := lightest()
+			nodes = append(nodes, node{weight: nodes[left].weight + nodes[right].weight, left: left, right: right})
+		}
+		depths := make([]int, len(nodes))
+		for i := len(nodes) - 1; i >= leaves; i-- {
+			depths[nodes[i].left] = depths[i] + 1
+			depths[nodes[i].right] = depths[i] + 1
+		}
+		max := 0
+		for _, d := range depths[:leaves] {
+			if d > max {
+				max = d
+			}
+		}
+		if max > limit {
+			continue
+		}
+		for i, n := range nodes[:leaves] {
+			lengths[n.left] = uint8(depths[i])
+		}
+		return lengths
+	}
+}
+
+// bitWriter packs bits starting with the least significant bit of every byte.
+type bitWriter struct {
+	out  []byte
+	bits uint64
+	n    uint
+}
+
+func (b *bitWriter) writeBits(n uint, value uint64) {
+	b.bits |= value << b.n
+	b.n += n
+	for b.n >= 8 {
+		b.out = append(b.out, byte(b.bits))
+		b.bits >>= 8
+		b.n -= 8
+	}
+}
+
+// bytes returns the written bits, padded with zeros to a whole byte.
+func (b *bitWriter) bytes() []byte {
+	if b.n > 0 {
+		return append(b.out, byte(b.bits))
+	}
+	return b.out
+}
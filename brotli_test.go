@@ -0,0 +1,126 @@
+package atomfeed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestBrotli compares the output with golden streams, which were checked with the reference decoder.
+// To regenerate a golden stream, compress the input with Brotli, make sure that a reference decoder
+// (like the brotli command line tool or github.com/andybalholm/brotli) restores the input and
+// put the hex encoded stream (or for large streams its hex encoded SHA-256 with prefix "sha256:") here.
+func TestBrotli(t *testing.T) {
+	block := noise(1 << 12)
+	tests := []struct {
+		name string
+		data []byte
+		want string // hex encoded stream or its SHA-256
+	}{
+		{"empty", nil, "3b"},
+		{"repetitions", []byte("<feed><entry></entry><entry></entry></feed>"), "0b1500000072718803d9cda80f832e890de00460b5dd6383b1f123941b2a666e3f56df4a70"},
+		{"meta-blocks", entryLines(3 << 19), "sha256:3b1f55b52e3739dc99717b940bc04634bcdf955fd584ec940e474859a287798c"},
+		{"long back-references", bytes.Join([][]byte{block, bytes.Repeat([]byte("<entry></entry>\n"), 3<<16), block}, nil), "sha256:0f80f9ea70ef094f6cbfea2cd1593909455a4127c6d1681c9d92ae99c6f6f1a1"},
+		{"incompressible", noise(1 << 16), "sha256:d9c2b84bd7c82150fc68396823423a96474985fcde693295c5736ae2bb3dab8b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w, err := Brotli.NewWriter(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write(tt.data)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			got := hex.EncodeToString(out.Bytes())
+			if strings.HasPrefix(tt.want, "sha256:") {
+				sum := sha256.Sum256(out.Bytes())
+				got = "sha256:" + hex.EncodeToString(sum[:])
+			}
+			if got != tt.want {
+				t.Errorf("Brotli = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// noise returns n incompressible bytes of a linear congruential generator.
+func noise(n int) []byte {
+	data := make([]byte, n)
+	x := uint32(1)
+	for i := range data {
+		x = x*1664525 + 1013904223
+		data[i] = byte(x >> 24)
+	}
+	return data
+}
+
+// entryLines returns at least n bytes of similar but distinct entries.
+func entryLines(n int) []byte {
+	b := &bytes.Buffer{}
+	for i := 0; b.Len() < n; i++ {
+		fmt.Fprintf(b, "<entry><id>tag:example.com,2005:blog.post-%d</id><title>Post %d</title></entry>\n", i, i%97)
+	}
+	return b.Bytes()
+}
+
+func Test_huffmanLengths(t *testing.T) {
+	skewed := make([]int, 704)
+	for i := range skewed {
+		if i%3 == 0 {
+			skewed[i] = 1 << uint(i%30)
+		}
+	}
+	tests := []struct {
+		name   string
+		counts []int
+		limit  int
+	}{
+		{"two symbols", []int{0, 3, 0, 1}, 15},
+		{"fibonacci", []int{1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144}, 5},
+		{"skewed", skewed, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lengths := huffmanLengths(tt.counts, tt.limit)
+			kraft := 0 // sum of 2^(limit-length), a complete code fills 2^limit
+			for s, l := range lengths {
+				if (l == 0) != (tt.counts[s] == 0) || int(l) > tt.limit {
+					t.Fatalf("huffmanLengths() = %v, invalid length of symbol %v", lengths, s)
+				}
+				if l > 0 {
+					kraft += 1 << uint(tt.limit-int(l))
+				}
+			}
+			if kraft != 1<<uint(tt.limit) {
+				t.Errorf("huffmanLengths() = %v, want a complete code", lengths)
+			}
+		})
+	}
+}
+
+func Test_distanceSymbol(t *testing.T) {
+	tests := []struct {
+		distance int
+		symbol   int
+		bits     uint
+		extra    int
+	}{
+		{1, 16, 1, 0},
+		{2, 16, 1, 1},
+		{3, 17, 1, 0},
+		{5, 18, 2, 0},
+		{brotliWindowSize, 55, 20, 1<<20 - 13},
+	}
+	for _, tt := range tests {
+		symbol, bits, extra := distanceSymbol(tt.distance)
+		if symbol != tt.symbol || bits != tt.bits || extra != tt.extra {
+			t.Errorf("distanceSymbol(%v) = %v, %v, %v, want %v, %v, %v", tt.distance, symbol, bits, extra, tt.symbol, tt.bits, tt.extra)
+		}
+	}
+}
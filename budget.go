@@ -0,0 +1,116 @@
+package atomfeed
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FitStrategy defines how FitSize reduces a feed, which exceeds its size limit.
+type FitStrategy int
+
+const (
+	// DropOldest drops the oldest entries.
+	DropOldest FitStrategy = iota
+	// TruncateOldest replaces the content of the oldest entries with their summary
+	// and drops the oldest entries only if the feed still exceeds its limit.
+	TruncateOldest
+)
+
+// BudgetSummaryLength is the length of summaries created by FitSize for truncated entries without summary.
+const BudgetSummaryLength = 280
+
+// FitSize returns a copy of the feed, whose encoding with o is at most limit bytes large,
+// e.g. for aggregators rejecting feeds above 512 KB. Entries are reduced from the oldest
+// (by updated date) to the newest according to strategy, while as many entries as possible are kept.
+// Truncated entries keep their summary or are summarized with BudgetSummaryLength characters and
+// lose their content, so they should provide an alternate link. Content without any text
+// and without a title to summarize it is kept. FitSize returns an error,
+// if the feed without entries exceeds the limit.
+func (f *Feed) FitSize(limit int, strategy FitStrategy, o EncodeOptions) (Feed, error) {
+	oldest := make([]int, len(f.Entries)) // indices of entries from oldest to newest
+	for i := range oldest {
+		oldest[i] = i
+	}
+	sort.SliceStable(oldest, func(i, j int) bool {
+		return f.Entries[oldest[i]].Updated.Time().Before(f.Entries[oldest[j]].Updated.Time())
+	})
+	// reduce returns the feed after step reductions: with TruncateOldest the first
+	// len(entries) steps truncate entries, all following steps drop entries.
+	reduce := func(step int) Feed {
+		truncated, dropped := 0, step
+		if strategy == TruncateOldest {
+			truncated, dropped = step, step-len(f.Entries)
+			if truncated > len(f.Entries) {
+				truncated = len(f.Entries)
+			}
+			if dropped < 0 {
+				dropped = 0
+			}
+		}
+		rank := make([]int, len(f.Entries))
+		for r, i := range oldest {
+			rank[i] = r
+		}
+		entries := []Entry{}
+		for i, e := range f.Entries {
+			if rank[i] < dropped {
+				continue
+			}
+			if rank[i] < truncated && e.Content != nil && e.Content.Source == "" {
+				e.Summarize(BudgetSummaryLength, DefaultEllipsis)
+				if e.Summary != nil { // content without any text can't be truncated
+					e.Content = nil
+				}
+			}
+			entries = append(entries, e)
+		}
+		feed := f.withEntries(entries)
+		if feed.Updated == nil { // keep the feed valid without entries
+			feed.Updated = f.Updated
+		}
+		return feed
+	}
+	steps := len(f.Entries) + 1
+	if strategy == TruncateOldest {
+		steps += len(f.Entries)
+	}
+	var err error
+	fits := func(step int) bool {
+		feed := reduce(step)
+		size, encodeErr := feed.encodedSize(o)
+		if encodeErr != nil && err == nil {
+			err = encodeErr
+		}
+		return size <= limit
+	}
+	// The size doesn't necessarily shrink with every step (a summary may be longer
+	// than its content), so the steps are tried one after another.
+	step := 0
+	for step < steps && fits(step) == false && err == nil {
+		step++
+	}
+	if err != nil {
+		return Feed{}, err
+	}
+	if step == steps {
+		return Feed{}, fmt.Errorf("feed: exceeds the size limit of %d bytes even without entries", limit)
+	}
+	return reduce(step), nil
+}
+
+// encodedSize returns the size of the feed encoded with o in bytes.
+func (f *Feed) encodedSize(o EncodeOptions) (int, error) {
+	c := &countingWriter{}
+	err := f.EncodeWithOptions(c, o)
+	return c.n, err
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
@@ -0,0 +1,112 @@
+package atomfeed
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedFitSize(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "blog")
+	long := []byte("<p>" + strings.Repeat("All work and no play makes Jack a dull boy. ", 50) + "<p>1 < 2")
+	entries := []Entry{}
+	for _, days := range []int{2, 0, 3, 1} { // unsorted
+		updated := now.AddDate(0, 0, -days)
+		entries = append(entries, NewEntry(NewEntryID(feedID, updated), updated.Format("Monday"), "https://example.com/"+updated.Format("Monday"), nil, updated, updated, nil, nil, long))
+	}
+	feed := NewFeed(feedID, NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, entries)
+	o := EncodeOptions{Compact: true}
+	full, _ := feed.encodedSize(o)
+	size := func(entries ...Entry) int {
+		f := feed.withEntries(entries)
+		size, _ := f.encodedSize(o)
+		return size
+	}
+	truncate := func(e Entry) Entry {
+		e.Summarize(BudgetSummaryLength, DefaultEllipsis)
+		e.Content = nil
+		return e
+	}
+	empty := size()
+	newest := size(entries[1])
+	allTruncated := size(truncate(entries[0]), truncate(entries[1]), truncate(entries[2]), truncate(entries[3]))
+	newestTruncated := size(truncate(entries[1]))
+
+	titles := func(f Feed) (titles []string, truncated int) {
+		for _, e := range f.Entries {
+			titles = append(titles, e.Title.Value)
+			if e.Content == nil {
+				truncated++
+			}
+		}
+		return titles, truncated
+	}
+	tests := []struct {
+		name          string
+		limit         int
+		strategy      FitStrategy
+		wantTitles    string
+		wantTruncated int
+	}{
+		{"fits", full, DropOldest, "Tuesday Thursday Monday Wednesday", 0},
+		{"drop oldest", full - 1, DropOldest, "Tuesday Thursday Wednesday", 0},
+		{"drop all but newest", newest, DropOldest, "Thursday", 0},
+		{"truncate oldest", full - 1, TruncateOldest, "Tuesday Thursday Monday Wednesday", 1},
+		{"truncate all", allTruncated, TruncateOldest, "Tuesday Thursday Monday Wednesday", 4},
+		{"truncate all and drop", newestTruncated, TruncateOldest, "Thursday", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := feed.FitSize(tt.limit, tt.strategy, o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size, _ := got.encodedSize(o); size > tt.limit {
+				t.Errorf("FitSize() = %v bytes, want at most %v bytes", size, tt.limit)
+			}
+			gotTitles, truncated := titles(got)
+			if strings.Join(gotTitles, " ") != tt.wantTitles || truncated != tt.wantTruncated {
+				t.Errorf("FitSize() = %v with %v truncated entries, want %v with %v", gotTitles, truncated, tt.wantTitles, tt.wantTruncated)
+			}
+			for _, e := range got.Entries {
+				if e.Content == nil && (e.Summary == nil || strings.HasPrefix(e.Summary.Value, "All work") == false) {
+					t.Errorf("FitSize() truncated entry %v without summary of its content", e.Title.Value)
+				}
+			}
+		})
+	}
+	if len(feed.Entries) != 4 || feed.Entries[0].Content == nil || feed.Entries[0].Summary != nil {
+		t.Error("FitSize() must not modify the original feed")
+	}
+
+	// truncating entries with a textless content and a long title makes them larger
+	grows := []Entry{}
+	for _, days := range []int{3, 2, 1} {
+		updated := now.AddDate(0, 0, -days)
+		title, content := updated.Format("Monday"), []byte(`<img src="gopher.png">`)
+		if days == 3 {
+			content = long
+		} else {
+			title += strings.Repeat(" and more", 30)
+		}
+		e := NewEntry(NewEntryID(feedID, updated), title, "https://example.com/"+updated.Format("Monday"), nil, updated, updated, nil, []byte("Summary"), content)
+		if days < 3 {
+			e.Summary = nil
+		}
+		grows = append(grows, e)
+	}
+	growing := NewFeed(feedID, NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, grows)
+	oldestTruncated := growing.withEntries([]Entry{truncate(grows[0]), grows[1], grows[2]})
+	limit, _ := oldestTruncated.encodedSize(o)
+	got, err := growing.FitSize(limit, TruncateOldest, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTitles, truncated := titles(got); len(gotTitles) != 3 || truncated != 1 {
+		t.Errorf("FitSize() of growing entries = %v with %v truncated entries, want all with 1", gotTitles, truncated)
+	}
+	if _, err := feed.FitSize(empty-1, TruncateOldest, o); err == nil {
+		t.Error("expected an error on a limit below the size of the feed without entries, got none")
+	}
+}
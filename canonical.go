@@ -0,0 +1,202 @@
+package atomfeed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// EncodeCanonical writes the Exclusive XML Canonicalization (without comments) of the atom:feed element.
+// The canonical form does not depend on formatting options, attribute order or the order of
+// namespace declarations, so it is suitable for content hashes, ETags and signatures.
+// The xml-stylesheet processing instruction is not part of the canonical form.
+//  https://www.w3.org/TR/xml-exc-c14n/
+func (f *Feed) EncodeCanonical(w io.Writer) error {
+	feed := *f
+	feed.Namespace = nsAtom // as for entries, the canonical form is always within the Atom namespace
+	feed.Stylesheet = nil
+	return encodeCanonical(w, &feed, 1)
+}
+
+// EncodeCanonical writes the Exclusive XML Canonicalization (without comments) of the atom:entry element.
+// The canonical form of an entry is the same, whether it is encoded on its own or within a feed.
+//  https://www.w3.org/TR/xml-exc-c14n/
+func (e *Entry) EncodeCanonical(w io.Writer) error {
+	return encodeCanonical(w, &Feed{Namespace: nsAtom, Entries: []Entry{*e}}, 2)
+}
+
+// Hash returns the hex encoded SHA-256 hash of the canonical form of the feed (see EncodeCanonical).
+func (f *Feed) Hash() (string, error) {
+	h := sha256.New()
+	if err := f.EncodeCanonical(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Hash returns the hex encoded SHA-256 hash of the canonical form of the entry (see EncodeCanonical).
+func (e *Entry) Hash() (string, error) {
+	h := sha256.New()
+	if err := e.EncodeCanonical(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// encodeCanonical encodes the feed and writes the canonical form of its first atom:feed (depth 1)
+// or atom:entry (depth 2) element.
+func encodeCanonical(w io.Writer, f *Feed, depth int) error {
+	b := &bytes.Buffer{}
+	if err := f.EncodeWithOptions(b, EncodeOptions{Compact: true}); err != nil {
+		return err
+	}
+	c := &canonicalizer{out: &bytes.Buffer{}}
+	if err := c.element(b.Bytes(), depth); err != nil {
+		return err
+	}
+	_, err := w.Write(c.out.Bytes())
+	return err
+}
+
+// canonicalizer writes the exclusive canonical form of an element.
+type canonicalizer struct {
+	out *bytes.Buffer
+	// scopes contains the namespaces declared in the input and rendered in the output per open element.
+	scopes []canonicalScope
+}
+
+type canonicalScope struct {
+	declared, rendered map[string]string // prefix → namespace URI, "" is the default namespace
+}
+
+// element canonicalizes the first element at depth (the document element has depth 1),
+// whose local name is "feed" or "entry".
+func (c *canonicalizer) element(data []byte, depth int) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	c.scopes = []canonicalScope{{declared: map[string]string{"": ""}, rendered: map[string]string{"": ""}}}
+	target := map[int]string{1: "feed", 2: "entry"}[depth]
+	level, inside := 0, false
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			return fmt.Errorf("canonicalize: missing %v element", target)
+		}
+		if err != nil {
+			return err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			level++
+			if inside == false && level == depth && t.Name.Local == target {
+				inside = true
+			}
+			c.start(t, inside)
+		case xml.EndElement:
+			if inside {
+				c.out.WriteString("</" + qualifiedName(t.Name) + ">")
+			}
+			c.scopes = c.scopes[:len(c.scopes)-1]
+			if inside && level == depth {
+				return nil
+			}
+			level--
+		case xml.CharData:
+			if inside {
+				c.out.WriteString(canonicalText.Replace(string(t)))
+			}
+		case xml.ProcInst:
+			if inside {
+				c.out.WriteString("<?" + t.Target)
+				if len(t.Inst) > 0 {
+					c.out.WriteString(" ")
+					c.out.Write(t.Inst)
+				}
+				c.out.WriteString("?>")
+			}
+		}
+	}
+}
+
+// start writes a start element with the namespace declarations, which it visibly utilizes,
+// followed by its sorted attributes.
+func (c *canonicalizer) start(t xml.StartElement, render bool) {
+	parent := c.scopes[len(c.scopes)-1]
+	scope := canonicalScope{declared: copyMap(parent.declared), rendered: copyMap(parent.rendered)}
+	attrs := []xml.Attr{}
+	for _, attr := range t.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			scope.declared[attr.Name.Local] = attr.Value
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			scope.declared[""] = attr.Value
+		default:
+			attrs = append(attrs, attr)
+		}
+	}
+	if render == false {
+		// rendering starts at the selected element: nothing has been rendered so far
+		c.scopes = append(c.scopes, scope)
+		return
+	}
+	used := []string{t.Name.Space}
+	for _, attr := range attrs {
+		if attr.Name.Space != "" && attr.Name.Space != "xml" {
+			used = append(used, attr.Name.Space)
+		}
+	}
+	sort.Strings(used)
+	c.out.WriteString("<" + qualifiedName(t.Name))
+	for i, prefix := range used {
+		if i > 0 && prefix == used[i-1] {
+			continue
+		}
+		uri := scope.declared[prefix]
+		if rendered, ok := scope.rendered[prefix]; ok && rendered == uri {
+			continue
+		}
+		scope.rendered[prefix] = uri
+		if prefix == "" {
+			c.out.WriteString(` xmlns="` + canonicalAttr.Replace(uri) + `"`)
+		} else {
+			c.out.WriteString(" xmlns:" + prefix + `="` + canonicalAttr.Replace(uri) + `"`)
+		}
+	}
+	namespaceURI := func(a xml.Attr) string {
+		if a.Name.Space == "xml" {
+			return nsXML
+		}
+		return scope.declared[a.Name.Space]
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		a, b := namespaceURI(attrs[i]), namespaceURI(attrs[j])
+		if a != b {
+			return a < b
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+	for _, attr := range attrs {
+		c.out.WriteString(" " + qualifiedName(attr.Name) + `="` + canonicalAttr.Replace(attr.Value) + `"`)
+	}
+	c.out.WriteString(">")
+	c.scopes = append(c.scopes, scope)
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Escaping of text and attribute values in canonical XML.
+//  https://www.w3.org/TR/xml-c14n#ProcessingModel
+var (
+	canonicalText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	canonicalAttr = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
@@ -0,0 +1,104 @@
+package atomfeed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedEncodeCanonical(t *testing.T) {
+	feed, err := Decode(strings.NewReader(`<?xml version="1.0"?>
+<?xml-stylesheet href="feed.xsl" type="text/xsl"?>
+<a:feed xmlns:a="http://www.w3.org/2005/Atom" xml:lang="en" xmlns:georss="http://www.georss.org/georss">
+	<a:id>tag:example.com,2005:blog</a:id>
+	<a:title type="text">Fish &amp; Chips</a:title>
+	<a:link type="text/html" rel="alternate" href="https://example.com/?a=1&amp;b=2"/>
+	<a:updated>2017-12-21T08:30:15Z</a:updated>
+	<a:author><a:name>Go Pher</a:name></a:author>
+	<a:entry>
+		<a:id>tag:example.com,2005:blog.post-1</a:id>
+		<a:title>One</a:title>
+		<a:updated>2017-12-21T08:30:15Z</a:updated>
+		<georss:point>52.52 13.405</georss:point>
+		<a:content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Hello
+ <br/>World</p></div></a:content>
+	</a:entry>
+</a:feed>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := feed.EncodeCanonical(out); err != nil {
+		t.Fatal(err)
+	}
+	want := `<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en">` +
+		`<id>tag:example.com,2005:blog</id>` +
+		`<link href="https://example.com/?a=1&amp;b=2" rel="alternate" type="text/html"></link>` +
+		`<updated>2017-12-21T08:30:15Z</updated>` +
+		`<title type="text">Fish &amp; Chips</title>` +
+		`<author><name>Go Pher</name></author>` +
+		`<entry><id>tag:example.com,2005:blog.post-1</id><title>One</title><updated>2017-12-21T08:30:15Z</updated>` +
+		`<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Hello` + "\n" + ` <br></br>World</p></div></content>` +
+		`<georss:point xmlns:georss="http://www.georss.org/georss">52.52 13.405</georss:point></entry>` +
+		`</feed>`
+	if out.String() != want {
+		t.Errorf("EncodeCanonical() =\n%v\n\nwant:\n%v", out, want)
+	}
+
+	// the canonical form does not depend on formatting
+	formatted := &bytes.Buffer{}
+	if err := feed.EncodeWithOptions(formatted, EncodeOptions{Indent: "\t", SelfClosing: true, SortAttributes: true}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(formatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := feed.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := decoded.Hash(); got != hash || len(hash) != 64 {
+		t.Errorf("Hash() of reformatted feed = %v, want %v", got, hash)
+	}
+	decoded.Entries[0].Title.Value = "Two"
+	if got, _ := decoded.Hash(); got == hash {
+		t.Error("Hash() did not change for a modified feed")
+	}
+}
+
+func TestEntryEncodeCanonical(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	entry := NewEntry(NewID("tag:example.com,2005:blog.post-1"), "One", "https://example.com/1", nil, now, time.Time{}, nil, nil, []byte("<p>1 > 0</p>"))
+	entry.Geo = NewGeoPoint(52.52, 13.405)
+	out := &bytes.Buffer{}
+	if err := entry.EncodeCanonical(out); err != nil {
+		t.Fatal(err)
+	}
+	want := `<entry xmlns="http://www.w3.org/2005/Atom"><id>tag:example.com,2005:blog.post-1</id><title>One</title>` +
+		`<link href="https://example.com/1" rel="alternate" type="text/html"></link><updated>2017-12-21T08:30:15Z</updated>` +
+		`<content type="html">&lt;p&gt;1 &gt; 0&lt;/p&gt;</content>` +
+		`<georss:point xmlns:georss="http://www.georss.org/georss">52.52 13.405</georss:point></entry>`
+	if out.String() != want {
+		t.Errorf("EncodeCanonical() =\n%v\n\nwant:\n%v", out, want)
+	}
+
+	// same hash within a feed
+	feed := NewFeed(NewFeedID("example.com", now, "blog"), NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, []Entry{entry})
+	data := &bytes.Buffer{}
+	if err := feed.Encode(data); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.Entries[0].Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := entry.Hash(); got != want {
+		t.Errorf("Hash() of decoded entry = %v, want %v", got, want)
+	}
+}
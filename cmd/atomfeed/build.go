@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denisbrodbeck/atomfeed"
+	"github.com/denisbrodbeck/atomfeed/source"
+)
+
+// buildConfig is the configuration of the build command, which is read from a flat YAML file:
+//  authority: example.com           # domain or email address owned at the time of creation
+//  created: 2005-12-21              # the feed ID is tag:example.com,2005-12-21:blog
+//  specifier: blog
+//  title: example.com blog
+//  subtitle: Get the very latest news from the net.
+//  base_url: https://example.com    # alternate link and base of permalinks of posts
+//  feed_url: https://example.com/feed.atom
+//  authors: [Go Pher <gopher@example.com>]
+//  entries: content/posts           # directory of Markdown and HTML posts, or
+//  manifest: entries.jsonl          # JSON lines manifest
+//  output: public/feed.atom         # "-" writes to stdout
+//  page_size: 20                    # optional pagination
+//
+// Paths are relative to the directory of the configuration file.
+type buildConfig struct {
+	authority, specifier      string
+	created                   time.Time
+	title, subtitle           string
+	baseURL, feedURL          string
+	authors                   []*atomfeed.Person
+	entries, manifest, output string
+	pageSize                  int
+}
+
+// manifestEntry is a single line of a JSON lines manifest.
+type manifestEntry struct {
+	ID      string   `json:"id"` // optional, derived from date and URL if empty
+	Title   string   `json:"title"`
+	URL     string   `json:"url"`
+	Date    string   `json:"date"`
+	Updated string   `json:"updated"`
+	Author  string   `json:"author"`
+	Tags    []string `json:"tags"`
+	Summary string   `json:"summary"`
+	Content string   `json:"content"` // html
+}
+
+// build generates a feed from a directory of posts or a JSON lines manifest.
+func build(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "feed.yaml", "path to the configuration file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: atomfeed build [--config feed.yaml]")
+		return 2
+	}
+	c, err := readBuildConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v: %v\n", *configFile, err)
+		return 1
+	}
+	feed, err := c.feed()
+	if err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	if err := feed.Verify(); err != nil {
+		fmt.Fprintf(stderr, "atomfeed: invalid feed:\n%v\n", err)
+		return 1
+	}
+	pages := feed.Pages(c.pageSize, func(page int) string { return pageName(c.feedURL, page) })
+	// all pages are encoded before any file is written, so that a failing page never leaves a partial set
+	encoded := &bytes.Buffer{}
+	for _, page := range pages {
+		if err := page.Encode(encoded); err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			return 1
+		}
+		encoded.WriteString("\n")
+	}
+	if c.output == "-" {
+		if _, err := encoded.WriteTo(stdout); err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	for i, page := range pages {
+		if err := page.WriteFiles(pageName(c.output, i+1), atomfeed.EncodeOptions{}); err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+func readBuildConfig(file string) (*buildConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	values, err := source.ParseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	value := func(key string) string {
+		if v := values[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	dir := filepath.Dir(file)
+	relative := func(p string) string {
+		if p == "" || p == "-" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	c := &buildConfig{
+		authority: value("authority"),
+		specifier: value("specifier"),
+		title:     value("title"),
+		subtitle:  value("subtitle"),
+		baseURL:   value("base_url"),
+		feedURL:   value("feed_url"),
+		entries:   relative(value("entries")),
+		manifest:  relative(value("manifest")),
+		output:    relative(value("output")),
+	}
+	for _, key := range []string{"authority", "created", "specifier", "title", "base_url"} {
+		if value(key) == "" {
+			return nil, fmt.Errorf("missing %v", key)
+		}
+	}
+	created, err := atomfeed.ParseDate(value("created"))
+	if err != nil {
+		return nil, fmt.Errorf("created: %v", err)
+	}
+	c.created = created.Time()
+	if c.feedURL == "" {
+		c.feedURL = strings.TrimSuffix(c.baseURL, "/") + "/feed.atom"
+	}
+	if c.output == "" {
+		c.output = filepath.Join(dir, "feed.atom")
+	}
+	if (c.entries == "") == (c.manifest == "") {
+		return nil, fmt.Errorf("either entries or manifest must be set")
+	}
+	if size := value("page_size"); size != "" {
+		if c.pageSize, err = strconv.Atoi(size); err != nil || c.pageSize < 0 {
+			return nil, fmt.Errorf("invalid page_size %q", size)
+		}
+	}
+	if c.pageSize > 0 && c.output == "-" {
+		return nil, fmt.Errorf("pagination requires an output file")
+	}
+	for _, a := range values["authors"] {
+		c.authors = append(c.authors, parsePerson(a))
+	}
+	return c, nil
+}
+
+// parsePerson parses persons in the formats "Name <email>" and "Name".
+func parsePerson(value string) *atomfeed.Person {
+	if a, err := mail.ParseAddress(value); err == nil {
+		return atomfeed.NewPerson(a.Name, a.Address, "")
+	}
+	return atomfeed.NewPerson(value, "", "")
+}
+
+func (c *buildConfig) feed() (*atomfeed.Feed, error) {
+	feedID := atomfeed.NewFeedID(c.authority, c.created, c.specifier)
+	var author *atomfeed.Person
+	if len(c.authors) > 0 {
+		author = c.authors[0]
+	}
+	entries := []atomfeed.Entry{}
+	var err error
+	if c.entries != "" {
+		entries, err = source.Walk(c.entries, source.Config{FeedID: feedID, BaseURL: c.baseURL, Author: author})
+	} else {
+		entries, err = readManifest(c.manifest, feedID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated := time.Now()
+	if len(entries) > 0 {
+		updated = time.Time{}
+		for _, e := range entries {
+			if t := e.Updated.Time(); t.After(updated) {
+				updated = t
+			}
+		}
+	}
+	feed := atomfeed.NewFeed(feedID, author, c.title, c.subtitle, c.baseURL, c.feedURL, updated, entries)
+	if c.subtitle == "" {
+		feed.Subtitle = nil
+	}
+	for _, a := range c.authors[minInt(1, len(c.authors)):] {
+		feed.Contributor = append(feed.Contributor, *a)
+	}
+	return &feed, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// readManifest reads entries from a JSON lines manifest, one JSON object per line.
+func readManifest(file string, feedID atomfeed.ID) ([]atomfeed.Entry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := []atomfeed.Entry{}
+	lines := map[string]int{} // entry ID → line
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024) // entries may carry large content
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		m := manifestEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", file, line, err)
+		}
+		e, err := m.entry(feedID)
+		if err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", file, line, err)
+		}
+		if other, ok := lines[e.ID.Value]; ok {
+			return nil, fmt.Errorf("%v:%v: duplicate entry ID %v of line %v: set a unique id", file, line, e.ID.Value, other)
+		}
+		lines[e.ID.Value] = line
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// entry returns the atom entry of the manifest entry. Unless set, the ID is derived from the date.
+func (m *manifestEntry) entry(feedID atomfeed.ID) (atomfeed.Entry, error) {
+	date, err := atomfeed.ParseDate(m.Date)
+	if err != nil {
+		return atomfeed.Entry{}, fmt.Errorf("date: %v", err)
+	}
+	updated := date
+	if m.Updated != "" {
+		if updated, err = atomfeed.ParseDate(m.Updated); err != nil {
+			return atomfeed.Entry{}, fmt.Errorf("updated: %v", err)
+		}
+	}
+	id := atomfeed.NewEntryID(feedID, date.Time())
+	if m.ID != "" {
+		id = atomfeed.NewID(m.ID)
+	}
+	var author *atomfeed.Person
+	if m.Author != "" {
+		author = parsePerson(m.Author)
+	}
+	return atomfeed.NewEntry(id, m.Title, m.URL, author, updated.Time(), date.Time(), m.Tags, []byte(m.Summary), []byte(m.Content)), nil
+}
+
+// pageName returns the file name or URL of a page: "feed.atom" for the first page, "feed-2.atom" for the second page.
+func pageName(name string, page int) string {
+	if page == 1 {
+		return name
+	}
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + strconv.Itoa(page) + ext
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildManifest(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{"build", "--config", "testdata/build/manifest.yaml"}, nil, stdout, stderr); status != 0 {
+		t.Fatalf("run() = %v, want 0 (stderr: %v)", status, stderr)
+	}
+	for _, want := range []string{
+		`<id>tag:example.com,2005-12-21:blog</id>`,
+		`<updated>2018-02-17T10:00:00Z</updated>`,
+		"<author>\n    <name>Go Pher</name>\n    <email>gopher@example.com</email>\n  </author>",
+		"<contributor>\n    <name>Jane Doe</name>\n  </contributor>",
+		`<id>tag:example.com,2005-12-21:blog.post-20171221083015</id>`,
+		`<id>tag:example.com,2005-12-21:blog.release</id>`,
+		`<link href="https://example.com/feed.atom" rel="self"`,
+	} {
+		if strings.Contains(stdout.String(), want) == false {
+			t.Errorf("build output is missing %s\n\ngot:\n%v", want, stdout)
+		}
+	}
+}
+
+func TestBuildPosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomfeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	posts, _ := filepath.Abs("testdata/build/posts")
+	config := filepath.Join(dir, "feed.yaml")
+	data := "authority: example.com\ncreated: 2005-12-21\nspecifier: blog\ntitle: example.com blog\n" +
+		"base_url: https://example.com/posts/\nauthors: [Go Pher <gopher@example.com>]\n" +
+		"entries: " + posts + "\noutput: public.atom\npage_size: 1\n"
+	if err := ioutil.WriteFile(config, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{"build", "--config", config}, nil, stdout, stderr); status != 0 {
+		t.Fatalf("run() = %v, want 0 (stderr: %v)", status, stderr)
+	}
+	first, err := ioutil.ReadFile(filepath.Join(dir, "public.atom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ioutil.ReadFile(filepath.Join(dir, "public-2.atom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<link href="https://example.com/posts/feed-2.atom" rel="next"`,
+		`<title>Release</title>`,
+		`&lt;p&gt;Version 1.0 is out.&lt;/p&gt;`,
+	} {
+		if strings.Contains(string(first), want) == false {
+			t.Errorf("first page is missing %s\n\ngot:\n%s", want, first)
+		}
+	}
+	for _, want := range []string{
+		`<link href="https://example.com/posts/feed.atom" rel="previous"`,
+		`<title>Hello World</title>`,
+	} {
+		if strings.Contains(string(second), want) == false {
+			t.Errorf("second page is missing %s\n\ngot:\n%s", want, second)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantErr    string
+	}{
+		{"missing config", []string{"build", "--config", "testdata/build/missing.yaml"}, 1, "missing.yaml"},
+		{"invalid feed", []string{"build", "--config", "testdata/build/invalid.yaml"}, 1, "missing author field"},
+		{"duplicate entry IDs", []string{"build", "--config", "testdata/build/duplicate.yaml"}, 1, "duplicate.jsonl:2: duplicate entry ID tag:example.com,2005-12-21:blog.post-20171221000000 of line 1: set a unique id"},
+		{"arguments", []string{"build", "feed.yaml"}, 2, "usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if status := run(tt.args, nil, stdout, stderr); status != tt.wantStatus {
+				t.Fatalf("run() = %v, want %v (stderr: %v)", status, tt.wantStatus, stderr)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("run() wrote output %q, want none", stdout)
+			}
+			if strings.Contains(stderr.String(), tt.wantErr) == false {
+				t.Errorf("run() error = %v, want %v", stderr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+// formats lists the feed formats supported by convert.
+var formats = []string{"atom", "rss", "json"}
+
+// convert converts a feed between the Atom, RSS 2.0 and JSON Feed formats.
+func convert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	from := flags.String("from", "", "format of the input: atom, rss or json (detected if empty)")
+	to := flags.String("to", "atom", "format of the output: atom, rss or json")
+	report := flags.Bool("report", false, "list information lost in the conversion on stderr")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 || isFormat(*to) == false || (*from != "" && isFormat(*from) == false) {
+		fmt.Fprintln(stderr, "usage: atomfeed convert [--from atom|rss|json] [--to atom|rss|json] [--report] [feed]")
+		return 2
+	}
+	in := bufio.NewReader(stdin)
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		in = bufio.NewReader(file)
+	}
+	if *from == "" {
+		*from = detectFormat(in)
+	}
+	f, losses, err := decodeFeed(in, *from)
+	if err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	out := &bytes.Buffer{}
+	switch *to {
+	case "atom":
+		err = f.Encode(out)
+		out.WriteString("\n")
+	case "rss":
+		losses = append(losses, f.RSSLosses()...)
+		err = f.EncodeRSS(out)
+		out.WriteString("\n")
+	case "json":
+		losses = append(losses, f.JSONLosses()...)
+		err = f.EncodeJSON(out)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	if _, err := out.WriteTo(stdout); err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	if *report {
+		for _, loss := range losses {
+			fmt.Fprintf(stderr, "lost: %v\n", loss)
+		}
+	}
+	return 0
+}
+
+func isFormat(format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// detectFormat guesses the format of a feed by its first bytes.
+func detectFormat(r *bufio.Reader) string {
+	head, _ := r.Peek(1024)
+	head = bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(head, []byte("{")):
+		return "json"
+	case bytes.Contains(head, []byte("<rss")):
+		return "rss"
+	}
+	return "atom"
+}
+
+func decodeFeed(r io.Reader, format string) (*atomfeed.Feed, []string, error) {
+	switch format {
+	case "rss":
+		return atomfeed.DecodeRSS(r)
+	case "json":
+		return atomfeed.DecodeJSON(r)
+	}
+	f, err := atomfeed.Decode(r)
+	return f, nil, err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantOut    string
+		wantReport string
+	}{
+		{"rss to atom", []string{"convert", "--from", "rss", "--to", "atom", "testdata/feed.rss"}, 0, `<feed xmlns="http://www.w3.org/2005/Atom">`, ""},
+		{"detect rss", []string{"convert", "--to", "json", "--report", "testdata/feed.rss"}, 0, `"content_html": "Go 1.10 is out."`, `lost: item Go 1.10 released: no guid, link "https://blog.golang.org/go1.10" is used as id`},
+		{"atom to rss", []string{"convert", "--to", "rss", "--report", "testdata/valid.atom"}, 0, `<rss version="2.0"`, `lost: feed: RSS has no feed id, "tag:example.com,2005:blog" is dropped`},
+		{"unknown format", []string{"convert", "--to", "html", "testdata/valid.atom"}, 2, "", ""},
+		{"wrong format", []string{"convert", "--from", "json", "testdata/valid.atom"}, 1, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if status := run(tt.args, nil, stdout, stderr); status != tt.wantStatus {
+				t.Fatalf("run() = %v, want %v (stderr: %v)", status, tt.wantStatus, stderr)
+			}
+			if strings.Contains(stdout.String(), tt.wantOut) == false {
+				t.Errorf("run() output = %v, want %v", stdout, tt.wantOut)
+			}
+			if strings.Contains(stderr.String(), tt.wantReport) == false {
+				t.Errorf("run() report = %v, want %v", stderr, tt.wantReport)
+			}
+		})
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{"convert", "--to", "atom"}, strings.NewReader(`{"version": "https://jsonfeed.org/version/1.1", "title": "Blog", "items": []}`), stdout, stderr); status != 0 {
+		t.Fatalf("convert of stdin = %v (stderr: %v)", status, stderr)
+	}
+	if strings.Contains(stdout.String(), "<title>Blog</title>") == false {
+		t.Errorf("convert of stdin = %v, want detected json feed", stdout)
+	}
+}
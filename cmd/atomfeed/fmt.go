@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+// format re-encodes feeds through Feed.EncodeWithOptions.
+// Elements and attributes unknown to the atomfeed package are dropped: format warns about them
+// and refuses to overwrite files with -w, which would lose them.
+func format(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write result to the source file instead of stdout")
+	options := atomfeed.EncodeOptions{}
+	flags.BoolVar(&options.Compact, "compact", false, "write without line breaks and indentation")
+	flags.StringVar(&options.Indent, "indent", atomfeed.DefaultIndent, "indentation of nested elements")
+	flags.BoolVar(&options.SelfClosing, "self-closing", false, "write empty elements as self-closing tags")
+	flags.BoolVar(&options.SortAttributes, "sort-attrs", false, "sort attributes alphabetically")
+	options.Report = func(change string) {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", change)
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(stderr, "atomfeed: cannot use -w with stdin")
+			return 2
+		}
+		data, err := ioutil.ReadAll(stdin)
+		if err == nil {
+			err = formatData(data, false, stdout, stderr, options)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	status := 0
+	for _, file := range flags.Args() {
+		if err := formatFile(file, *write, stdout, stderr, options); err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v: %v\n", file, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func formatFile(file string, write bool, stdout, stderr io.Writer, options atomfeed.EncodeOptions) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if write == false {
+		return formatData(data, false, stdout, stderr, options)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	out := &bytes.Buffer{}
+	if err := formatData(data, true, out, stderr, options); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out.Bytes(), info.Mode())
+}
+
+// formatData formats the feed document and writes it to w.
+// Lost elements, attributes and namespace declarations are an error in strict mode and a warning otherwise.
+func formatData(data []byte, strict bool, w, stderr io.Writer, options atomfeed.EncodeOptions) error {
+	out := &bytes.Buffer{}
+	if err := formatFeed(bytes.NewReader(data), out, options); err != nil {
+		return err
+	}
+	if lost := lostItems(documentItems(data), documentItems(out.Bytes())); len(lost) > 0 {
+		err := fmt.Errorf("formatting drops %v, which are unknown to atomfeed", strings.Join(lost, ", "))
+		if strict {
+			return fmt.Errorf("%v: not written", err)
+		}
+		fmt.Fprintf(stderr, "atomfeed: warning: %v\n", err)
+	}
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func formatFeed(r io.Reader, w io.Writer, options atomfeed.EncodeOptions) error {
+	f, err := atomfeed.Decode(r)
+	if err != nil {
+		return err
+	}
+	if err := f.EncodeWithOptions(w, options); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// documentItems returns the elements, attributes and namespace declarations of a well-formed document
+// and how often they occur. Names are qualified by their namespace, so that prefixes may change.
+func documentItems(data []byte) map[string]int {
+	items := map[string]int{}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return items
+		}
+		start, ok := t.(xml.StartElement)
+		if ok == false {
+			continue
+		}
+		items["element "+qualifiedName(start.Name)]++
+		for _, attr := range start.Attr {
+			if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+				items["namespace declaration "+attr.Value]++
+				continue
+			}
+			items["attribute "+qualifiedName(attr.Name)]++
+		}
+	}
+}
+
+// lostItems returns the items, which occur less often in formatted than in original, sorted by name.
+func lostItems(original, formatted map[string]int) []string {
+	lost := []string{}
+	for item, count := range original {
+		if n := count - formatted[item]; n == 1 {
+			lost = append(lost, item)
+		} else if n > 1 {
+			lost = append(lost, fmt.Sprintf("%v (%d times)", item, n))
+		}
+	}
+	sort.Strings(lost)
+	return lost
+}
+
+// qualifiedName returns a name in Clark notation, e.g. {http://purl.org/syndication/thread/1.0}count.
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return "{" + name.Space + "}" + name.Local
+}
@@ -0,0 +1,75 @@
+/*
+Command atomfeed validates and formats atom feeds.
+
+Usage:
+
+	atomfeed validate feed.atom...
+	atomfeed fmt [-w] [-compact] [-indent s] [-self-closing] [-sort-attrs] [feed.atom...]
+	atomfeed convert [--from atom|rss|json] [--to atom|rss|json] [--report] [feed]
+	atomfeed build [--config feed.yaml]
+	atomfeed serve [--addr localhost:8080] feed.atom
+
+Validate decodes the given feeds, verifies them and reports all issues with line and column numbers.
+It exits with status 1 if any issues were found.
+
+Fmt re-encodes the given feeds (or stdin) in the canonical format of the atomfeed package
+and writes them to stdout. The -w flag overwrites the files instead. The flags -compact, -indent,
+-self-closing and -sort-attrs control the formatting (see atomfeed.EncodeOptions).
+
+Convert converts a feed (or stdin) between the Atom, RSS 2.0 and JSON Feed formats and writes it to stdout.
+The input format is detected unless --from is given. The --report flag lists all information,
+which was lost in the conversion, on stderr.
+
+Build generates a feed from the feed metadata and entries source (a directory of Markdown and HTML posts
+or a JSON lines manifest) defined in a YAML configuration file. The feed is verified before it is written,
+optionally split into pages of page_size entries (feed.atom, feed-2.atom, ...).
+
+Serve starts a local HTTP server previewing a feed before it is published. It serves the raw feed at /feed
+and renders its entries, like feed readers display them, together with all validation issues at /.
+The preview reloads whenever the feed file changes.
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// commands maps the names of all sub commands to their implementation.
+// Each command returns the exit status.
+var commands = map[string]func(args []string, stdin io.Reader, stdout, stderr io.Writer) int{
+	"validate": validate,
+	"fmt":      format,
+	"convert":  convert,
+	"build":    build,
+	"serve":    serve,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	command, ok := commands[args[0]]
+	if ok == false {
+		fmt.Fprintf(stderr, "atomfeed: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return command(args[1:], stdin, stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  atomfeed validate feed.atom...
+  atomfeed fmt [-w] [-compact] [-indent s] [-self-closing] [-sort-attrs] [feed.atom...]
+  atomfeed convert [--from atom|rss|json] [--to atom|rss|json] [--report] [feed]
+  atomfeed build [--config feed.yaml]
+  atomfeed serve [--addr localhost:8080] feed.atom
+`)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantOut    string
+	}{
+		{"valid", []string{"validate", "testdata/valid.atom"}, 0, ""},
+		{"invalid", []string{"validate", "testdata/invalid.atom"}, 1, "testdata/invalid.atom:2:1: feed: missing title\ntestdata/invalid.atom:11:3: entry: missing title\n"},
+		{"invalid values", []string{"validate", "testdata/values.atom"}, 1, "testdata/values.atom:5:3: feed: updated: invalid date \"yesterday\": unknown date format\n" +
+			"testdata/values.atom:11:5: media:content: strconv.ParseInt: parsing \"wide\": invalid syntax\ntestdata/values.atom:13:3: entry: missing title\n"},
+		{"invalid elements", []string{"validate", "testdata/elements.atom"}, 1, "testdata/elements.atom:8:5: entry: ID cannot be empty\ntestdata/elements.atom:10:5: entry: missing title\n" +
+			"testdata/elements.atom:11:5: entry: invalid xhtml: content must be wrapped in a single div element of namespace \"http://www.w3.org/1999/xhtml\"\n"},
+		{"malformed", []string{"validate", "testdata/malformed.atom"}, 1, "testdata/malformed.atom:3: invalid xml: element <title> closed by </titel>\n"},
+		{"missing file", []string{"validate", "testdata/missing.atom"}, 1, ""},
+		{"no files", []string{"validate"}, 2, ""},
+		{"unknown command", []string{"lint"}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if status := run(tt.args, nil, stdout, stderr); status != tt.wantStatus {
+				t.Errorf("run() = %v, want %v (stderr: %v)", status, tt.wantStatus, stderr)
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("run() output = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{"fmt", "testdata/valid.atom"}, nil, stdout, stderr); status != 0 {
+		t.Fatalf("run() = %v, want 0 (stderr: %v)", status, stderr)
+	}
+	if want := "<feed xmlns=\"http://www.w3.org/2005/Atom\">\n  <id>tag:example.com,2005:blog</id>"; strings.Contains(stdout.String(), want) == false {
+		t.Errorf("fmt output = %v, want indented feed", stdout)
+	}
+
+	// stdin
+	stdin := bytes.NewReader(stdout.Bytes())
+	formatted := stdout.String()
+	stdout.Reset()
+	if status := run([]string{"fmt"}, stdin, stdout, stderr); status != 0 || stdout.String() != formatted {
+		t.Errorf("fmt of formatted feed = %v, want unchanged output", stdout)
+	}
+
+	// overwrite
+	dir, err := ioutil.TempDir("", "atomfeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "feed.atom")
+	data, _ := ioutil.ReadFile("testdata/valid.atom")
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if status := run([]string{"fmt", "-w", file}, nil, stdout, stderr); status != 0 || stdout.Len() != 0 {
+		t.Fatalf("run() = %v, output %q, want 0 and no output", status, stdout)
+	}
+	if got, _ := ioutil.ReadFile(file); string(got) != formatted {
+		t.Errorf("fmt -w wrote %q, want %q", got, formatted)
+	}
+	if status := run([]string{"fmt"}, strings.NewReader("<rss/>"), stdout, stderr); status != 1 {
+		t.Errorf("fmt of invalid feed = %v, want 1", status)
+	}
+
+	// unknown elements
+	extended := strings.Replace(string(data), "</feed>", `<ext:rating xmlns:ext="https://example.com/ext">5</ext:rating></feed>`, 1)
+	if err := ioutil.WriteFile(file, []byte(extended), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if status := run([]string{"fmt", "-w", file}, nil, stdout, stderr); status != 1 || strings.Contains(stderr.String(), "drops element {https://example.com/ext}rating, namespace declaration https://example.com/ext,") == false {
+		t.Errorf("fmt -w of extended feed = %v (stderr: %v), want 1 and dropped elements", status, stderr)
+	}
+	if got, _ := ioutil.ReadFile(file); string(got) != extended {
+		t.Errorf("fmt -w overwrote extended feed with %q", got)
+	}
+	threaded := strings.Replace(string(data), `<feed xmlns="http://www.w3.org/2005/Atom">`, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:thr="http://purl.org/syndication/thread/1.0">`, 1)
+	threaded = strings.Replace(threaded, `<title>One</title>`, `<title>One</title><link href="https://example.com/one" thr:count="3"/>`, 1)
+	if err := ioutil.WriteFile(file, []byte(threaded), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if status := run([]string{"fmt", "-w", file}, nil, stdout, stderr); status != 1 || strings.Contains(stderr.String(), "drops attribute {http://purl.org/syndication/thread/1.0}count, namespace declaration http://purl.org/syndication/thread/1.0,") == false {
+		t.Errorf("fmt -w of feed with extension attribute = %v (stderr: %v), want 1 and dropped attribute", status, stderr)
+	}
+	if got, _ := ioutil.ReadFile(file); string(got) != threaded {
+		t.Errorf("fmt -w overwrote feed with extension attribute with %q", got)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if status := run([]string{"fmt", file}, nil, stdout, stderr); status != 0 || stdout.Len() == 0 || strings.Contains(stderr.String(), "warning: formatting drops attribute {http://purl.org/syndication/thread/1.0}count") == false {
+		t.Errorf("fmt of feed with extension attribute = %v (stderr: %v), want 0, output and warning", status, stderr)
+	}
+
+	// formatting options
+	stdout.Reset()
+	if status := run([]string{"fmt", "-compact", "-self-closing", "testdata/valid.atom"}, nil, stdout, stderr); status != 0 {
+		t.Fatalf("run() = %v, want 0 (stderr: %v)", status, stderr)
+	}
+	if want := `<feed xmlns="http://www.w3.org/2005/Atom"><id>tag:example.com,2005:blog</id><updated>`; strings.Contains(stdout.String(), want) == false {
+		t.Errorf("fmt -compact output = %v, want %v", stdout, want)
+	}
+}
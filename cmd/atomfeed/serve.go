@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+// serve starts a local HTTP server previewing a feed file.
+func serve(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: atomfeed serve [--addr localhost:8080] feed.atom")
+		return 2
+	}
+	if _, err := os.Stat(flags.Arg(0)); err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	p := &preview{file: flags.Arg(0), interval: 500 * time.Millisecond}
+	fmt.Fprintf(stdout, "previewing %v on http://%v/\n", p.file, *addr)
+	if err := http.ListenAndServe(*addr, p.handler()); err != nil {
+		fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// preview serves a feed file, an HTML preview of its entries and change notifications.
+type preview struct {
+	file     string
+	interval time.Duration // interval between checks for file changes
+}
+
+// handler returns the routes of the preview server:
+//  /       HTML preview with validation panel, which reloads on file changes
+//  /feed   raw feed
+//  /events server-sent events announcing file changes
+func (p *preview) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", p.page)
+	mux.HandleFunc("/feed", p.feed)
+	mux.HandleFunc("/events", p.events)
+	return mux
+}
+
+// feed serves the feed file like a web server serves a published feed.
+func (p *preview) feed(w http.ResponseWriter, r *http.Request) {
+	data, modified, err := p.read()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, filepath.Base(p.file), modified, bytes.NewReader(data))
+}
+
+// page renders the entries of the feed similar to feed readers.
+// Content is sanitized with the default policy while it is rendered, like feed readers do.
+func (p *preview) page(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	data, _, err := p.read()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	feed, issues := validateFeed(data)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	err = previewTemplate.Execute(w, struct {
+		File   string
+		Feed   *atomfeed.Feed
+		Issues []issue
+	}{filepath.Base(p.file), feed, issues})
+	if err != nil {
+		fmt.Fprintf(w, "<p>atomfeed: %v</p>", template.HTMLEscapeString(err.Error()))
+	}
+}
+
+// events sends a server-sent event to the preview page whenever the feed file changes.
+func (p *preview) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if ok == false {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	last := p.modTime()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if modified := p.modTime(); modified.Equal(last) == false {
+				last = modified
+				fmt.Fprint(w, "data: reload\n\n")
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func (p *preview) read() ([]byte, time.Time, error) {
+	modified := p.modTime()
+	data, err := ioutil.ReadFile(p.file)
+	return data, modified, err
+}
+
+// modTime returns the modification time of the feed file or the zero time, if the file is missing.
+func (p *preview) modTime() time.Time {
+	info, err := os.Stat(p.file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// previewPolicy sanitizes html and xhtml of previewed feeds.
+var previewPolicy = atomfeed.DefaultPolicy()
+
+// previewFuncs are the template functions of the preview, whose safeContent sanitizes
+// html and xhtml before rendering them. The preview never trusts markup of the feed file.
+func previewFuncs() template.FuncMap {
+	funcs := atomfeed.TemplateFuncs()
+	render := funcs["safeContent"].(func(interface{}) (template.HTML, error))
+	funcs["safeContent"] = func(value interface{}) (template.HTML, error) {
+		switch v := value.(type) {
+		case *atomfeed.TextConstruct:
+			if v != nil {
+				t := *v
+				t.Value, t.ValueXML = sanitizeMarkup(t.Type, t.Value, t.ValueXML)
+				value = &t
+			}
+		case *atomfeed.Content:
+			if v != nil {
+				c := *v
+				c.Value, c.ValueXML = sanitizeMarkup(c.Type, c.Value, c.ValueXML)
+				value = &c
+			}
+		}
+		return render(value)
+	}
+	return funcs
+}
+
+// sanitizeMarkup sanitizes the value of html or the xml value of xhtml constructs.
+// Values of other types are escaped by safeContent.
+func sanitizeMarkup(contentType, value, valueXML string) (string, string) {
+	switch contentType {
+	case "html":
+		value = previewPolicy.Sanitize(value)
+	case "xhtml":
+		valueXML = atomfeed.WrapXHTML(previewPolicy.Sanitize(atomfeed.UnwrapXHTML(valueXML)))
+	}
+	return value, valueXML
+}
+
+var previewTemplate = template.Must(template.New("preview").Funcs(previewFuncs()).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Feed}}{{plainText .Feed.Title}}{{else}}{{.File}}{{end}} – atomfeed preview</title>
+<style>
+body { font-family: sans-serif; max-width: 46em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+.issues { border: 1px solid #d33; background: #fdf1f1; padding: 0.5em 1em; }
+.valid { border: 1px solid #3a3; background: #f1fdf1; padding: 0.5em 1em; }
+.meta { color: #666; font-size: 0.9em; }
+article { border-top: 1px solid #ddd; padding: 1em 0; }
+img { max-width: 100%; }
+</style>
+</head>
+<body>
+{{if .Issues}}<section class="issues">
+<strong>{{len .Issues}} issue(s) found in {{.File}}</strong>
+<ul>{{range .Issues}}<li>{{.}}</li>{{end}}</ul>
+</section>{{else}}<section class="valid">No issues found in {{.File}}.</section>{{end}}
+{{with .Feed}}<header>
+<h1>{{safeContent .Title}}</h1>
+{{with .Subtitle}}<p>{{safeContent .}}</p>{{end}}
+<p class="meta">Updated {{formatDate "2 Jan 2006 15:04 MST" .Updated}}{{with .Author}} by {{.Name}}{{end}} · <a href="/feed">raw feed</a></p>
+</header>
+{{range .Entries}}<article>
+<h2>{{with alternate .Links}}<a href="{{.}}">{{end}}{{safeContent .Title}}{{if alternate .Links}}</a>{{end}}</h2>
+<p class="meta">{{with .Published}}Published {{formatDate "2 Jan 2006 15:04 MST" .}} · {{end}}Updated {{formatDate "2 Jan 2006 15:04 MST" .Updated}}{{with .Author}} by {{.Name}}{{end}}{{range .Categories}} · {{.Term}}{{end}}</p>
+{{if .Content}}{{safeContent .Content}}{{else}}{{with .Summary}}{{safeContent .}}{{end}}{{end}}
+</article>{{end}}{{end}}
+<script>new EventSource("/events").onmessage = function() { location.reload(); };</script>
+</body>
+</html>
+`))
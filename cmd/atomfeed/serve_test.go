@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomfeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "feed.atom")
+	write := func(content string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(url string) (*http.Response, string) {
+		res, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return res, string(body)
+	}
+	valid, _ := ioutil.ReadFile("testdata/valid.atom")
+	write(string(valid))
+	server := httptest.NewServer((&preview{file: file, interval: 10 * time.Millisecond}).handler())
+	defer server.Close()
+
+	res, body := get(server.URL + "/feed")
+	if res.StatusCode != http.StatusOK || body != string(valid) {
+		t.Errorf("GET /feed = %v %q, want 200 and the feed file", res.Status, body)
+	}
+	if got := res.Header.Get("Content-Type"); got != "application/atom+xml; charset=utf-8" {
+		t.Errorf("GET /feed Content-Type = %q", got)
+	}
+	if res.Header.Get("Last-Modified") == "" {
+		t.Error("GET /feed is missing the Last-Modified header")
+	}
+
+	_, body = get(server.URL + "/")
+	for _, want := range []string{"No issues found in feed.atom", "<h1>Blog</h1>", "<h2>One</h2>", `new EventSource("/events")`} {
+		if strings.Contains(body, want) == false {
+			t.Errorf("GET / is missing %s\n\ngot:\n%v", want, body)
+		}
+	}
+
+	write(`<feed xmlns="http://www.w3.org/2005/Atom"><id>tag:example.com,2005:blog</id><title>Blog</title>
+<updated>2017-12-21T08:30:15Z</updated><author><name>Go Pher</name></author>
+<entry><id>tag:example.com,2005:blog.post-1</id><updated>2017-12-21T08:30:15Z</updated><link href="https://example.com/one"/>
+<content type="html">&lt;p onclick="steal()"&gt;Hello&lt;script&gt;steal()&lt;/script&gt;&lt;/p&gt;</content></entry>
+<entry><id>tag:example.com,2005:blog.post-2</id><updated>2017-12-21T08:30:15Z</updated><title type="html">Two &lt;img src=x onerror=steal()&gt;</title>
+<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>World<script>steal()</script></p></div></content></entry>
+<subtitle type="html">Go &lt;script&gt;steal()</subtitle>
+</feed>`)
+	_, body = get(server.URL + "/")
+	for _, want := range []string{"1 issue(s) found in feed.atom", "3:1: entry: missing title", `<a href="https://example.com/one">`, "<p>Hello</p>", `Two <img src="x"/>`, "<p>World</p>", "<p>Go </p>"} {
+		if strings.Contains(body, want) == false {
+			t.Errorf("GET / is missing %s\n\ngot:\n%v", want, body)
+		}
+	}
+	if strings.Contains(body, "steal()") {
+		t.Errorf("GET / must render sanitized content, got:\n%v", body)
+	}
+
+	if res, _ := get(server.URL + "/missing"); res.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /missing = %v, want 404", res.Status)
+	}
+}
+
+func TestServeEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomfeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "feed.atom")
+	if err := ioutil.WriteFile(file, []byte("<feed/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer((&preview{file: file, interval: 10 * time.Millisecond}).handler())
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if got := res.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("GET /events Content-Type = %q, want text/event-stream", got)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	if err != nil || line != "data: reload\n" {
+		t.Errorf("GET /events = %q, %v, want a reload event", line, err)
+	}
+}
+
+func TestServeUsage(t *testing.T) {
+	if status := run([]string{"serve"}, nil, ioutil.Discard, ioutil.Discard); status != 2 {
+		t.Errorf("serve without feed = %v, want 2", status)
+	}
+	if status := run([]string{"serve", "testdata/missing.atom"}, nil, ioutil.Discard, ioutil.Discard); status != 1 {
+		t.Errorf("serve of missing feed = %v, want 1", status)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+func validate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: atomfeed validate feed.atom...")
+		return 2
+	}
+	status := 0
+	for _, file := range flags.Args() {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "atomfeed: %v\n", err)
+			status = 1
+			continue
+		}
+		_, issues := validateFeed(data)
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%v:%v\n", file, issue)
+		}
+		if len(issues) > 0 {
+			status = 1
+		}
+	}
+	return status
+}
+
+// issue is a problem of a feed at a position within the document.
+type issue struct {
+	line, column int
+	err          error
+}
+
+func (i issue) String() string {
+	if i.column == 0 {
+		return fmt.Sprintf("%v: %v", i.line, i.err)
+	}
+	return fmt.Sprintf("%v:%v: %v", i.line, i.column, i.err)
+}
+
+// validateFeed decodes and verifies a feed document and returns the decoded feed, if the document is well-formed.
+// Issues are reported at the position of the offending child element of atom:feed or atom:entry,
+// or else at the position of the atom:feed or atom:entry element itself.
+// Elements with invalid values (like a non-numeric width) are reported and skipped, so that
+// the rest of the feed is still verified.
+func validateFeed(data []byte) (*atomfeed.Feed, []issue) {
+	root, err := parseElements(data)
+	if err != nil {
+		if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+			return nil, []issue{{line: syntaxErr.Line, err: fmt.Errorf("invalid xml: %v", syntaxErr.Msg)}}
+		}
+		return nil, []issue{{line: 1, column: 1, err: err}}
+	}
+	f, issues := decodeElements(data, root)
+	if f == nil {
+		return nil, issues
+	}
+	if err := f.Verify(); err != nil {
+		for _, err := range err.Errors {
+			if strings.HasPrefix(err.Error(), "feed: ") {
+				issues = append(issues, root.issue(data, err, "feed: "))
+			}
+		}
+	}
+	entries := root.entries()
+	for i, e := range f.Entries {
+		err := e.Verify()
+		if err == nil {
+			continue
+		}
+		for _, err := range err.Errors {
+			if i < len(entries) {
+				issues = append(issues, entries[i].issue(data, err, "entry: "))
+			} else {
+				issues = append(issues, root.issue(data, err, "entry: "))
+			}
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].line < issues[j].line || (issues[i].line == issues[j].line && issues[i].column < issues[j].column)
+	})
+	return f, issues
+}
+
+// decodeElements decodes the feed document. Children of atom:feed and atom:entry elements,
+// which fail to decode, are reported and removed from the document.
+func decodeElements(data []byte, root element) (*atomfeed.Feed, []issue) {
+	f, err := atomfeed.Decode(bytes.NewReader(data))
+	if err == nil {
+		return f, nil
+	}
+	if _, err := root.decode(data); err != nil {
+		return nil, []issue{root.at(data, err)}
+	}
+	issues := []issue{}
+	invalid := []element{}
+	for _, child := range root.children {
+		if child.name != "entry" {
+			if _, err := root.decode(data, child); err != nil {
+				issues = append(issues, child.at(data, fmt.Errorf("%v: %v", child.rawName, err)))
+				invalid = append(invalid, child)
+			}
+			continue
+		}
+		for _, grandchild := range child.children {
+			if _, err := root.decode(data, child, grandchild); err != nil {
+				issues = append(issues, grandchild.at(data, fmt.Errorf("%v: %v", grandchild.rawName, err)))
+				invalid = append(invalid, grandchild)
+			}
+		}
+	}
+	cleaned := []byte{}
+	last := int64(0)
+	for _, e := range invalid {
+		cleaned = append(cleaned, data[last:e.start]...)
+		last = e.end
+	}
+	cleaned = append(cleaned, data[last:]...)
+	if f, err = atomfeed.Decode(bytes.NewReader(cleaned)); err != nil {
+		return nil, append(issues, root.at(data, err))
+	}
+	return f, issues
+}
+
+// element is the position of an element and its children within a feed document.
+// Only the children of the root element and their children are recorded.
+type element struct {
+	name       string // local name
+	rawName    string // qualified name as written in the document
+	start, end int64  // offsets of the start tag and after the end tag
+	startEnd   int64  // offset after the start tag
+	children   []element
+}
+
+// parseElements returns the root element of the document.
+func parseElements(data []byte) (element, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	stack := []*element{{}}
+	for {
+		offset := d.InputOffset()
+		t, err := d.Token()
+		if err == io.EOF {
+			if len(stack[0].children) == 0 {
+				return element{}, fmt.Errorf("missing feed element")
+			}
+			return stack[0].children[0], nil
+		}
+		if err != nil {
+			return element{}, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			e := element{name: t.Name.Local, rawName: rawName(data[offset:]), start: offset, startEnd: d.InputOffset()}
+			if len(stack) <= 3 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+				stack = append(stack, &parent.children[len(parent.children)-1])
+			} else {
+				stack = append(stack, nil)
+			}
+		case xml.EndElement:
+			if e := stack[len(stack)-1]; e != nil {
+				e.end = d.InputOffset()
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// rawName returns the qualified name of the start tag at the beginning of data.
+func rawName(data []byte) string {
+	end := bytes.IndexAny(data, " \t\r\n/>")
+	if end < 0 {
+		return ""
+	}
+	return string(data[1:end])
+}
+
+// entries returns the atom:entry children.
+func (e element) entries() []element {
+	entries := []element{}
+	for _, child := range e.children {
+		if child.name == "entry" {
+			entries = append(entries, child)
+		}
+	}
+	return entries
+}
+
+// decode decodes the root element with only the given descendant: a child or a child's child.
+func (e element) decode(data []byte, path ...element) (*atomfeed.Feed, error) {
+	doc := append([]byte{}, data[e.start:e.startEnd]...)
+	if e.startEnd == e.end { // empty-element tag
+		return atomfeed.Decode(bytes.NewReader(doc))
+	}
+	ends := []string{e.rawName}
+	for i, p := range path {
+		if i == len(path)-1 {
+			doc = append(doc, data[p.start:p.end]...)
+		} else {
+			doc = append(doc, data[p.start:p.startEnd]...)
+			ends = append(ends, p.rawName)
+		}
+	}
+	for i := len(ends) - 1; i >= 0; i-- {
+		doc = append(doc, "</"+ends[i]+">"...)
+	}
+	return atomfeed.Decode(bytes.NewReader(doc))
+}
+
+// errorElements maps the messages of verification errors, which don't start with the name
+// of the offending element, to the local name of that element.
+var errorElements = []struct {
+	message, element string
+}{
+	{"missing title", "title"},
+	{"missing updated date", "updated"},
+	{"ID cannot be empty", "id"},
+	{"invalid xhtml", "content"},
+	{"invalid content", "content"},
+	{"invalid mime type", "content"},
+	{"need a summary because content", "content"},
+}
+
+// issue returns an issue at the child element named by the error message after prefix
+// (like "updated" in "entry: updated: invalid date" or "title" in "entry: missing title", see errorElements)
+// or else at the element itself, e.g. if the child element is missing.
+func (e element) issue(data []byte, err error, prefix string) issue {
+	field := strings.TrimPrefix(err.Error(), prefix)
+	mapped := false
+	for _, m := range errorElements {
+		if strings.HasPrefix(field, m.message) {
+			field, mapped = m.element, true
+			break
+		}
+	}
+	if end := strings.IndexAny(field, ": "); end >= 0 && mapped == false {
+		field = field[:end]
+	}
+	for _, child := range e.children {
+		if strings.EqualFold(child.name, field) {
+			return child.at(data, err)
+		}
+	}
+	return e.at(data, err)
+}
+
+// at returns an issue at the position of the element.
+func (e element) at(data []byte, err error) issue {
+	line, column := position(data, e.start)
+	return issue{line, column, err}
+}
+
+// position converts a byte offset into a line and column number (counted in characters), both starting at 1.
+func position(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, column
+}
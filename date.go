@@ -0,0 +1,171 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time returns the time of the date element.
+// Dates without time, which were created with the deprecated Value field, are parsed with ParseDate.
+// Time returns the zero time for invalid dates.
+func (d *Date) Time() time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	if d.t.IsZero() && d.Value != "" {
+		if parsed, err := ParseDate(d.Value); err == nil {
+			return parsed.t
+		}
+	}
+	return d.t
+}
+
+// Set changes the time of the date element.
+func (d *Date) Set(t time.Time) {
+	d.t = t
+	d.Value = t.Format(time.RFC3339Nano)
+}
+
+// String returns the date in RFC 3339 format with the precision of the time
+// or the text of an invalid date as decoded.
+func (d *Date) String() string {
+	t := d.Time()
+	if t.IsZero() && d != nil && d.Value != "" {
+		return d.Value
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalXML encodes the date in RFC 3339 format with the precision of the time, e.g. 2017-12-21T08:30:15.123Z.
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, d.CommonAttributes.attrs()...)
+	return e.EncodeElement(d.String(), start)
+}
+
+// UnmarshalXML decodes a date element, accepting all formats supported by ParseDate.
+// Invalid dates are kept as text in Value and reported by Verify.
+func (d *Date) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	*d = Date{}
+	for _, attr := range start.Attr {
+		commonAttribute(&d.CommonAttributes, attr)
+	}
+	value, err := innerText(dec)
+	if err != nil {
+		return err
+	}
+	d.Value = strings.TrimSpace(value)
+	if parsed, err := ParseDate(value); err == nil {
+		d.t = parsed.t
+	}
+	return nil
+}
+
+// dateLayouts lists the formats commonly found in feeds, which are accepted by ParseDate.
+// Time zone abbreviations are replaced by their offset (see rfc822Zones) before parsing.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	time.RFC1123Z,
+	"Mon, _2 Jan 2006 15:04:05 -0700",
+	"Mon, _2 Jan 2006 15:04 -0700",
+	"_2 Jan 2006 15:04:05 -0700",
+	"_2 Jan 2006 15:04 -0700",
+	time.RFC822Z,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"20060102",
+}
+
+// rfc822Zones maps the time zone abbreviations defined by RFC 822 to their offsets.
+// Military zones other than "Z" are ignored, as their signs are commonly mixed up.
+//  https://tools.ietf.org/html/rfc822#section-5.1
+var rfc822Zones = map[string]string{
+	"UT": "+0000", "UTC": "+0000", "GMT": "+0000", "Z": "+0000",
+	"EST": "-0500", "EDT": "-0400",
+	"CST": "-0600", "CDT": "-0500",
+	"MST": "-0700", "MDT": "-0600",
+	"PST": "-0800", "PDT": "-0700",
+}
+
+// minEpochDigits is the minimum number of digits of Unix time stamps accepted by ParseDate,
+// which keeps dates like "2017" or "20171222" from being read as seconds since epoch.
+const minEpochDigits = 10
+
+// ParseDate leniently parses dates in the formats commonly found in feeds:
+// RFC 3339, RFC 822/RFC 1123 (as used by RSS), ISO 8601 without time zone
+// and Unix time stamps (seconds since epoch, at least ten digits).
+// Dates without time zone are interpreted as UTC. Time zone abbreviations other than
+// the ones defined by RFC 822 (UT, GMT, Z, EST, EDT, CST, CDT, MST, MDT, PST, PDT) are rejected.
+func ParseDate(value string) (*Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("invalid date: date is empty")
+	}
+	normalized := value
+	if i := strings.LastIndexByte(value, ' '); i >= 0 && isZoneAbbreviation(value[i+1:]) {
+		offset, ok := rfc822Zones[strings.ToUpper(value[i+1:])]
+		if ok == false {
+			return nil, fmt.Errorf("invalid date %q: unknown time zone %s", value, value[i+1:])
+		}
+		normalized = value[:i+1] + offset
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			d := &Date{}
+			d.Set(t)
+			return d, nil
+		}
+	}
+	if len(strings.TrimPrefix(value, "-")) >= minEpochDigits {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			d := &Date{}
+			d.Set(time.Unix(seconds, 0).UTC())
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q: unknown date format", value)
+}
+
+// isZoneAbbreviation reports whether s consists of letters only.
+func isZoneAbbreviation(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if isASCIILetter(s[i]) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeDates converts all dates of the feed and its entries into the given location,
+// e.g. time.UTC, so that the encoded feed uses a single fixed time zone.
+func (f *Feed) NormalizeDates(loc *time.Location) {
+	normalize := func(d *Date) {
+		if t := d.Time(); t.IsZero() == false {
+			d.Set(t.In(loc))
+		}
+	}
+	normalize(f.Updated)
+	if f.DublinCore != nil {
+		normalize(f.Modified)
+	}
+	for i := range f.Entries {
+		e := &f.Entries[i]
+		normalize(e.Updated)
+		normalize(e.Published)
+		if e.Source != nil {
+			normalize(e.Source.Updated)
+		}
+		if e.DublinCore != nil {
+			normalize(e.Modified)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package atomfeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"rfc3339", "2012-12-21T08:30:15Z", want, false},
+		{"rfc3339 with offset", "2012-12-21T10:30:15+02:00", want, false},
+		{"rfc3339 with fraction", "2012-12-21T08:30:15.5Z", want.Add(500 * time.Millisecond), false},
+		{"space instead of T", "2012-12-21 08:30:15Z", want, false},
+		{"rfc1123z", "Fri, 21 Dec 2012 08:30:15 +0000", want, false},
+		{"rfc1123", "Fri, 21 Dec 2012 08:30:15 GMT", want, false},
+		{"rfc822 single digit day", "Sun, 2 Dec 2012 08:30:15 +0000", want.AddDate(0, 0, -19), false},
+		{"rfc822 without weekday", "21 Dec 2012 08:30:15 +0000", want, false},
+		{"rfc822 without seconds", "Fri, 21 Dec 2012 08:30 +0000", want.Add(-15 * time.Second), false},
+		{"iso8601 without zone", "2012-12-21T08:30:15", want, false},
+		{"iso8601 with space", " 2012-12-21 08:30:15\n", want, false},
+		{"date only", "2012-12-21", time.Date(2012, time.December, 21, 0, 0, 0, 0, time.UTC), false},
+		{"epoch", "1356078615", want, false},
+		{"rfc822 eastern zone", "Fri, 21 Dec 2012 03:30:15 EST", want, false},
+		{"rfc822 pacific daylight zone", "Fri, 21 Dec 2012 01:30:15 PDT", want, false},
+		{"rfc822 universal zone", "21 Dec 2012 08:30 UT", want.Add(-15 * time.Second), false},
+		{"unknown zone", "Fri, 21 Dec 2012 08:30:15 CET", time.Time{}, true},
+		{"basic date", "20121221", time.Date(2012, time.December, 21, 0, 0, 0, 0, time.UTC), false},
+		{"year only", "2012", time.Time{}, true},
+		{"empty", "", time.Time{}, true},
+		{"garbage", "yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Time().Equal(tt.want) == false {
+				t.Errorf("ParseDate() = %v, want %v", got.Time(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMarshalXML(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	d := NewDate(time.Date(2012, time.December, 21, 9, 30, 15, 999, berlin))
+	d.CommonAttributes = &CommonAttributes{Lang: "de"}
+	out, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"entry"`
+		Updated *Date    `xml:"updated"`
+	}{Updated: d})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), `<entry><updated xml:lang="de">2012-12-21T09:30:15.000000999+01:00</updated></entry>`; got != want {
+		t.Errorf("Marshal() = %v, want %v", got, want)
+	}
+
+	d.Set(time.Date(2017, time.December, 22, 8, 30, 15, 0, time.UTC))
+	if got, want := d.String(), "2017-12-22T08:30:15Z"; got != want || d.Value != want {
+		t.Errorf("Set() = %v (Value %v), want %v", got, d.Value, want)
+	}
+
+	var decoded Date
+	if err := xml.Unmarshal([]byte(`<updated>2017-12-21T08:30:15.123456Z</updated>`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := xml.Marshal(decoded); string(out) != `<Date>2017-12-21T08:30:15.123456Z</Date>` {
+		t.Errorf("Marshal() of decoded date = %s, want fractional seconds", out)
+	}
+
+	deprecated := &Date{Value: "2012-12-21T08:30:15Z"}
+	if got, want := deprecated.Time(), time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC); got.Equal(want) == false {
+		t.Errorf("Time() of Value = %v, want %v", got, want)
+	}
+}
+
+func TestFeedNormalizeDates(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	now := time.Date(2012, time.December, 21, 9, 30, 15, 0, berlin)
+	feed := NewFeed(NewID("tag:example.com,2012:blog"), NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com", "https://example.com/feed.atom", now, []Entry{
+		NewEntry(NewID("tag:example.com,2012:blog.post-1"), "Post", "https://example.com/1", nil, now, now, nil, nil, []byte("post")),
+	})
+	feed.NormalizeDates(time.UTC)
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "+01:00") || strings.Count(out.String(), "2012-12-21T08:30:15Z") != 3 {
+		t.Errorf("NormalizeDates() did not convert all dates to UTC:\n%v", out.String())
+	}
+}
+
+func TestDecodeLenientDates(t *testing.T) {
+	doc := `<feed xmlns="http://www.w3.org/2005/Atom">
+  <updated>Fri, 21 Dec 2012 08:30:15 GMT</updated>
+  <entry>
+    <updated>2012-12-21 08:30:15</updated>
+  </entry>
+</feed>`
+	feed, err := Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	if !feed.Updated.Time().Equal(want) || !feed.Entries[0].Updated.Time().Equal(want) {
+		t.Errorf("Decode() dates = %v, %v, want %v", feed.Updated, feed.Entries[0].Updated, want)
+	}
+	feed, err = Decode(strings.NewReader(`<feed><updated>yesterday</updated><entry><updated>Fri, 21 Dec 2012 08:30:15 +0000</updated></entry></feed>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Updated.Value != "yesterday" || feed.Updated.Time().IsZero() == false || feed.Entries[0].Updated.Time().Equal(want) == false {
+		t.Errorf("Decode() dates = %q, %v, want invalid date kept and valid entry date", feed.Updated.Value, feed.Entries[0].Updated)
+	}
+	if err := checkDate(feed.Updated); err == nil || strings.Contains(err.Error(), "yesterday") == false {
+		t.Errorf("checkDate() error = %v, want error on invalid date", err)
+	}
+}
@@ -0,0 +1,236 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Decode reads the XML encoding of an atom:feed element from the stream.
+//
+// Elements and attributes of supported extensions (like Media RSS) are recognized
+// by their namespace, regardless of the prefixes used within the document.
+// An xml-stylesheet processing instruction is kept in Feed.Stylesheet.
+func Decode(r io.Reader) (*Feed, error) {
+	f := &Feed{}
+	d := xml.NewTokenDecoder(&stylesheetReader{r: prefixReader{xml.NewDecoder(r)}, feed: f})
+	if err := d.Decode(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// newDecoder returns a decoder, which maps elements and attributes of
+// known namespaces to the prefixed names used by the structs of this package.
+func newDecoder(r io.Reader) *xml.Decoder {
+	return xml.NewTokenDecoder(prefixReader{xml.NewDecoder(r)})
+}
+
+// prefixReader is a xml.TokenReader, which replaces the namespace of known
+// elements and attributes with the prefix used within the struct tags.
+type prefixReader struct {
+	d *xml.Decoder
+}
+
+func (r prefixReader) Token() (xml.Token, error) {
+	t, err := r.d.Token()
+	switch t := t.(type) {
+	case xml.StartElement:
+		t.Name = prefixed(t.Name)
+		for i := range t.Attr {
+			t.Attr[i].Name = prefixed(t.Attr[i].Name)
+		}
+		return t, err
+	case xml.EndElement:
+		t.Name = prefixed(t.Name)
+		return t, err
+	}
+	return t, err
+}
+
+func prefixed(name xml.Name) xml.Name {
+	if prefix, ok := prefixes[name.Space]; ok {
+		return xml.Name{Local: prefix + ":" + name.Local}
+	}
+	return name
+}
+
+// UnmarshalXML decodes an atom:content element.
+// Depending on the type attribute the content is either kept as text or as raw XML.
+func (c *Content) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*c = Content{}
+	for _, attr := range start.Attr {
+		switch prefixed(attr.Name).Local {
+		case "type":
+			c.Type = attr.Value
+		case "src":
+			c.Source = attr.Value
+		default:
+			commonAttribute(&c.CommonAttributes, attr)
+		}
+	}
+	if isXMLContentType(c.Type) {
+		value, err := innerXML(d, start.Name.Space)
+		c.ValueXML = value
+		return err
+	}
+	value, err := innerText(d)
+	if err != nil {
+		return err
+	}
+	if isTextContentType(c.Type) {
+		c.Value = value
+	} else {
+		// all other types are base64 encoded
+		c.Value = strings.TrimSpace(value)
+		c.base64Encoded = c.Value != ""
+	}
+	return nil
+}
+
+// UnmarshalXML decodes a text construct like atom:title.
+func (t *TextConstruct) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*t = TextConstruct{}
+	for _, attr := range start.Attr {
+		if prefixed(attr.Name).Local == "type" {
+			t.Type = attr.Value
+		} else {
+			commonAttribute(&t.CommonAttributes, attr)
+		}
+	}
+	var err error
+	if t.Type == "xhtml" {
+		t.ValueXML, err = innerXML(d, start.Name.Space)
+	} else {
+		t.Value, err = innerText(d)
+	}
+	return err
+}
+
+// commonAttribute decodes xml:base and xml:lang attributes into ca
+// and reports whether attr was one of them.
+func commonAttribute(ca **CommonAttributes, attr xml.Attr) bool {
+	name := prefixed(attr.Name).Local
+	if name != "xml:base" && name != "xml:lang" {
+		return false
+	}
+	if *ca == nil {
+		*ca = &CommonAttributes{}
+	}
+	if name == "xml:base" {
+		(*ca).Base = attr.Value
+	} else {
+		(*ca).Lang = attr.Value
+	}
+	return true
+}
+
+// attrs returns the xml:base and xml:lang attributes for custom marshalers.
+func (ca *CommonAttributes) attrs() []xml.Attr {
+	attrs := []xml.Attr{}
+	if ca == nil {
+		return attrs
+	}
+	if ca.Base != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xml:base"}, Value: ca.Base})
+	}
+	if ca.Lang != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xml:lang"}, Value: ca.Lang})
+	}
+	return attrs
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// innerText consumes all tokens up to the end of the current element and returns the contained character data.
+func innerText(d *xml.Decoder) (string, error) {
+	b := &strings.Builder{}
+	for depth := 0; ; {
+		t, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return b.String(), nil
+			}
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+}
+
+// innerXML consumes all tokens up to the end of the current element and returns them as XML.
+// The namespace space is the default namespace of the current element.
+// Empty void elements of XHTML (like <br/>) and empty elements of other namespaces (like SVG)
+// are written as self-closing tags. Other empty XHTML elements keep their end tag,
+// because HTML parsers don't close <span/>.
+func innerXML(d *xml.Decoder, space string) (string, error) {
+	b := &strings.Builder{}
+	spaces := []string{space}       // default namespace of every open element
+	declared := map[string]string{} // namespace URI to prefix
+	selfClosing := false            // whether the last written start tag is open and may be closed by "/>"
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := t.(xml.EndElement); ok && selfClosing {
+			selfClosing = false
+			spaces = spaces[:len(spaces)-1]
+			b.WriteString("/>")
+			continue
+		}
+		if selfClosing {
+			selfClosing = false
+			b.WriteString(">")
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			b.WriteString("<" + t.Name.Local)
+			if t.Name.Space != spaces[len(spaces)-1] {
+				b.WriteString(` xmlns="` + attrEscaper.Replace(t.Name.Space) + `"`)
+			}
+			spaces = append(spaces, t.Name.Space)
+			for _, attr := range t.Attr {
+				name := prefixed(attr.Name)
+				switch {
+				case name.Space == "" && name.Local == "xmlns":
+					continue // default namespace is declared above
+				case name.Space == "xmlns":
+					declared[attr.Value] = name.Local
+					name.Local = "xmlns:" + name.Local
+				case name.Space != "":
+					if prefix, ok := declared[name.Space]; ok {
+						name.Local = prefix + ":" + name.Local
+					}
+				}
+				b.WriteString(" " + name.Local + `="` + attrEscaper.Replace(attr.Value) + `"`)
+			}
+			if t.Name.Space != nsXHTML || voidElements[t.Name.Local] {
+				selfClosing = true
+				continue
+			}
+			b.WriteString(">")
+		case xml.EndElement:
+			if len(spaces) == 1 {
+				return b.String(), nil
+			}
+			spaces = spaces[:len(spaces)-1]
+			b.WriteString("</" + t.Name.Local + ">")
+		case xml.CharData:
+			b.WriteString(textEscaper.Replace(string(t)))
+		case xml.Comment:
+			b.WriteString("<!--" + string(t) + "-->")
+		case xml.ProcInst:
+			b.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package atomfeed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	feed, err := Decode(strings.NewReader(basicBlogFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := feed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != basicBlogFeed {
+		t.Errorf("Decode() did not survive a round trip\n\ngot:\n%v\n\nwant:\n%v", got, basicBlogFeed)
+	}
+}
+
+func TestDecodeContent(t *testing.T) {
+	doc := `<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en">
+  <entry>
+    <content type="xhtml" xml:base="https://example.com/"><div xmlns="http://www.w3.org/1999/xhtml"><p>Go &amp; <a href="/gopher">gopher</a><br/><span class="icon"></span><img src="a.png"></img><svg xmlns="http://www.w3.org/2000/svg"><rect width="1"></rect></svg></p></div></content>
+    <summary type="html">&lt;em&gt;summary&lt;/em&gt;</summary>
+  </entry>
+  <entry>
+    <content type="image/gif">
+      R0lGODdhAQABAIAAAP///////ywAAAAAAQABAAACAkQBADs=
+    </content>
+  </entry>
+</feed>`
+	feed, err := Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.CommonAttributes == nil || feed.Lang != "en" {
+		t.Errorf("Decode() lost xml:lang attribute: %+v", feed.CommonAttributes)
+	}
+	xhtml := feed.Entries[0].Content
+	if want := `<div xmlns="http://www.w3.org/1999/xhtml"><p>Go &amp; <a href="/gopher">gopher</a><br/><span class="icon"></span><img src="a.png"/><svg xmlns="http://www.w3.org/2000/svg"><rect width="1"/></svg></p></div>`; xhtml.ValueXML != want || xhtml.Value != "" {
+		t.Errorf("Decode() xhtml content = %q, want %q", xhtml.ValueXML, want)
+	}
+	if xhtml.CommonAttributes == nil || xhtml.Base != "https://example.com/" {
+		t.Errorf("Decode() lost xml:base attribute: %+v", xhtml.CommonAttributes)
+	}
+	if summary := feed.Entries[0].Summary; summary.Value != "<em>summary</em>" || summary.ValueXML != "" {
+		t.Errorf("Decode() html summary = %+v", summary)
+	}
+	gif := feed.Entries[1].Content
+	if gif.Value != "R0lGODdhAQABAIAAAP///////ywAAAAAAQABAAACAkQBADs=" || !gif.base64Encoded {
+		t.Errorf("Decode() base64 content = %+v", gif)
+	}
+}
@@ -0,0 +1,200 @@
+package atomfeed
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FeedDiff describes the changes between two versions of a feed.
+type FeedDiff struct {
+	Changes  []Change    // changed metadata of the feed
+	Added    []Entry     // entries only present in the updated feed
+	Removed  []Entry     // entries only present in the old feed
+	Modified []EntryDiff // entries present in both feeds with changed fields
+}
+
+// EntryDiff describes the changes of a single entry.
+type EntryDiff struct {
+	Entry   Entry // the updated version of the entry
+	Changes []Change
+}
+
+// Change describes the old and new value of a single field.
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff compares two versions of a feed. Entries are matched by their ID.
+// Added and modified entries are reported in the order of the updated feed,
+// removed entries in the order of the old feed. A nil feed is treated as an empty feed.
+func Diff(old, updated *Feed) *FeedDiff {
+	if old == nil {
+		old = &Feed{}
+	}
+	if updated == nil {
+		updated = &Feed{}
+	}
+	d := &FeedDiff{Changes: diffFields(feedFields(old), feedFields(updated))}
+	oldEntries := map[string]Entry{}
+	for _, e := range old.Entries {
+		oldEntries[e.ID.Value] = e
+	}
+	newEntries := map[string]bool{}
+	for _, e := range updated.Entries {
+		newEntries[e.ID.Value] = true
+		prev, ok := oldEntries[e.ID.Value]
+		if ok == false {
+			d.Added = append(d.Added, e)
+			continue
+		}
+		if changes := diffFields(entryFields(&prev), entryFields(&e)); len(changes) > 0 {
+			d.Modified = append(d.Modified, EntryDiff{Entry: e, Changes: changes})
+		}
+	}
+	for _, e := range old.Entries {
+		if newEntries[e.ID.Value] == false {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+	return d
+}
+
+// Empty reports whether both feeds are equal.
+func (d *FeedDiff) Empty() bool {
+	return len(d.Changes) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// String returns a human-readable report of all changes.
+// Added entries are prefixed with "+", removed entries with "-" and modified entries with "~".
+func (d *FeedDiff) String() string {
+	b := &strings.Builder{}
+	for _, c := range d.Changes {
+		fmt.Fprintf(b, "feed %v\n", c)
+	}
+	for _, e := range d.Added {
+		fmt.Fprintf(b, "+ %v %q\n", e.ID.Value, textValue(e.Title))
+	}
+	for _, e := range d.Removed {
+		fmt.Fprintf(b, "- %v %q\n", e.ID.Value, textValue(e.Title))
+	}
+	for _, e := range d.Modified {
+		fmt.Fprintf(b, "~ %v %q\n", e.Entry.ID.Value, textValue(e.Entry.Title))
+		for _, c := range e.Changes {
+			fmt.Fprintf(b, "    %v\n", c)
+		}
+	}
+	return b.String()
+}
+
+// String returns the change in the form `field: "old" → "new"`.
+// Long values are shortened.
+func (c Change) String() string {
+	return fmt.Sprintf("%v: %q → %q", c.Field, truncateText(collapseSpace(c.Old), 60, DefaultEllipsis), truncateText(collapseSpace(c.New), 60, DefaultEllipsis))
+}
+
+// field is a comparable representation of a feed or entry field.
+type field struct {
+	name  string
+	value string
+}
+
+func diffFields(old, updated []field) []Change {
+	changes := []Change{}
+	for i := range old {
+		if old[i].value != updated[i].value {
+			changes = append(changes, Change{Field: old[i].name, Old: old[i].value, New: updated[i].value})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func feedFields(f *Feed) []field {
+	return []field{
+		{"id", f.ID.Value},
+		{"title", textValue(f.Title)},
+		{"subtitle", textValue(f.Subtitle)},
+		{"updated", dateString(f.Updated)},
+		{"links", linksString(f.Links)},
+		{"author", personString(f.Author)},
+		{"categories", categoriesString(f.Categories)},
+		{"rights", textValue(f.Copyright)},
+		{"icon", iconString(f.Icon)},
+		{"logo", logoString(f.Logo)},
+	}
+}
+
+func entryFields(e *Entry) []field {
+	return []field{
+		{"title", textValue(e.Title)},
+		{"updated", dateString(e.Updated)},
+		{"published", dateString(e.Published)},
+		{"links", linksString(e.Links)},
+		{"author", personString(e.Author)},
+		{"categories", categoriesString(e.Categories)},
+		{"summary", contentString(e.Summary)},
+		{"content", contentString(e.Content)},
+	}
+}
+
+// dateString returns the date in UTC, so that equal instants in different time zones compare equal.
+func dateString(d *Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.Time().UTC().Format(time.RFC3339Nano)
+}
+
+func linksString(links []Link) string {
+	values := []string{}
+	for _, l := range links {
+		values = append(values, strings.TrimSpace(fmt.Sprintf("%v %v %v", l.Rel, l.Href, l.Type)))
+	}
+	return strings.Join(values, ", ")
+}
+
+func categoriesString(categories []Category) string {
+	terms := []string{}
+	for _, c := range categories {
+		terms = append(terms, c.Term)
+	}
+	return strings.Join(terms, ", ")
+}
+
+func personString(p *Person) string {
+	if p == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v %v %v", p.Name, p.Email, p.URI))
+}
+
+func contentString(c *Content) string {
+	switch {
+	case c == nil:
+		return ""
+	case c.Source != "":
+		return c.Source
+	case isXMLContentType(c.Type):
+		return c.ValueXML
+	}
+	return c.Value
+}
+
+func iconString(i *Icon) string {
+	if i == nil {
+		return ""
+	}
+	return i.Value
+}
+
+func logoString(l *Logo) string {
+	if l == nil {
+		return ""
+	}
+	return l.Value
+}
@@ -0,0 +1,63 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	post := func(id, title string) Entry {
+		return NewEntry(NewID(id), title, "https://example.com/"+id, nil, now, time.Time{}, []string{"go"}, nil, []byte("<p>content</p>"))
+	}
+	old := &Feed{
+		Title:   &TextConstruct{Value: "Blog"},
+		Updated: NewDate(now),
+		Entries: []Entry{post("1", "One"), post("2", "Two"), post("3", "Three")},
+	}
+	modified := post("2", "Two, revised")
+	modified.Updated = NewDate(now.Add(time.Hour))
+	modified.Categories = append(modified.Categories, *NewCategory("news"))
+	updated := &Feed{
+		Title:   &TextConstruct{Value: "Blog"},
+		Updated: NewDate(now.In(time.FixedZone("CET", 3600))), // same instant
+		Entries: []Entry{post("4", "Four"), modified, post("3", "Three")},
+	}
+
+	d := Diff(old, updated)
+	if len(d.Changes) != 0 {
+		t.Errorf("Diff() feed changes = %v, want none", d.Changes)
+	}
+	if len(d.Added) != 1 || d.Added[0].ID.Value != "4" {
+		t.Errorf("Diff() added = %v, want entry 4", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ID.Value != "1" {
+		t.Errorf("Diff() removed = %v, want entry 1", d.Removed)
+	}
+	wantChanges := []Change{
+		{"title", "Two", "Two, revised"},
+		{"updated", "2017-12-21T08:30:15Z", "2017-12-21T09:30:15Z"},
+		{"categories", "go", "go, news"},
+	}
+	if len(d.Modified) != 1 || !reflect.DeepEqual(d.Modified[0].Changes, wantChanges) {
+		t.Errorf("Diff() modified = %v, want changes %v", d.Modified, wantChanges)
+	}
+
+	want := `+ 4 "Four"
+- 1 "One"
+~ 2 "Two, revised"
+    title: "Two" → "Two, revised"
+    updated: "2017-12-21T08:30:15Z" → "2017-12-21T09:30:15Z"
+    categories: "go" → "go, news"
+`
+	if got := d.String(); got != want {
+		t.Errorf("FeedDiff.String() = \n%v\nwant:\n%v", got, want)
+	}
+	if Diff(old, old).Empty() == false {
+		t.Error("Diff() of equal feeds is not empty")
+	}
+	if d := Diff(nil, old); len(d.Added) != 3 || len(d.Changes) != 2 {
+		t.Errorf("Diff(nil, feed) = %v, want all entries added and title and updated changed", d)
+	}
+}
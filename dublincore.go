@@ -0,0 +1,89 @@
+package atomfeed
+
+import "fmt"
+
+// DublinCore contains Dublin Core metadata elements of a feed or an entry,
+// which are commonly used by library catalogues and archives.
+//  http://dublincore.org/documents/dces/
+//  http://dublincore.org/documents/dcmi-terms/
+type DublinCore struct {
+	// Creators are the entities primarily responsible for making the resource.
+	Creators []string `xml:"dc:creator"`
+	// Subjects are keywords, key phrases or classification codes of the resource.
+	Subjects []string `xml:"dc:subject"`
+	// Identifier is an unambiguous reference to the resource, like an ISBN or a DOI.
+	Identifier string `xml:"dc:identifier,omitempty"`
+	// Rights contains information about rights held in and over the resource.
+	Rights string `xml:"dc:rights,omitempty"`
+	// Modified is the date on which the resource was changed.
+	Modified *Date `xml:"dcterms:modified"`
+}
+
+func (dc *DublinCore) usesElements() bool {
+	return dc != nil && (len(dc.Creators) > 0 || len(dc.Subjects) > 0 || dc.Identifier != "" || dc.Rights != "")
+}
+
+func (dc *DublinCore) usesTerms() bool {
+	return dc != nil && dc.Modified != nil
+}
+
+func usesDublinCore(f *Feed) bool {
+	if f.DublinCore.usesElements() {
+		return true
+	}
+	for _, e := range f.Entries {
+		if e.DublinCore.usesElements() {
+			return true
+		}
+	}
+	return false
+}
+
+func usesDublinCoreTerms(f *Feed) bool {
+	if f.DublinCore.usesTerms() {
+		return true
+	}
+	for _, e := range f.Entries {
+		if e.DublinCore.usesTerms() {
+			return true
+		}
+	}
+	return false
+}
+
+// withCreator returns a copy of dc with the given creator added, unless it is already present.
+func (dc *DublinCore) withCreator(creator string) *DublinCore {
+	c := &DublinCore{}
+	if dc != nil {
+		*c = *dc
+	}
+	for _, existing := range c.Creators {
+		if existing == creator {
+			return c
+		}
+	}
+	c.Creators = append(append([]string{}, c.Creators...), creator)
+	return c
+}
+
+func checkDublinCore(dc *DublinCore) error {
+	if dc == nil {
+		return nil
+	}
+	for _, c := range dc.Creators {
+		if c == "" {
+			return fmt.Errorf("dc:creator cannot be empty")
+		}
+	}
+	for _, s := range dc.Subjects {
+		if s == "" {
+			return fmt.Errorf("dc:subject cannot be empty")
+		}
+	}
+	if dc.Modified != nil {
+		if err := checkDate(dc.Modified); err != nil {
+			return fmt.Errorf("dcterms:modified: %v", err)
+		}
+	}
+	return nil
+}
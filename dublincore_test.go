@@ -0,0 +1,102 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCatalogueFeed() Feed {
+	now := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "catalogue")
+	author := NewPerson("Go Pher", "", "")
+	entry := NewEntry(NewEntryID(feedID, now), "The Go Programming Language", "https://example.com/books/gopl", author, now, now, nil, nil, []byte("A book about Go."))
+	entry.DublinCore = &DublinCore{
+		Creators:   []string{"Alan A. A. Donovan", "Brian W. Kernighan"},
+		Subjects:   []string{"Programming languages"},
+		Identifier: "urn:isbn:9780134190440",
+		Rights:     "All rights reserved",
+		Modified:   NewDate(now),
+	}
+	return NewFeed(feedID, author, "Catalogue", "", "https://example.com", "https://example.com/feed.atom", now, []Entry{entry})
+}
+
+func TestDublinCoreEncode(t *testing.T) {
+	feed := newCatalogueFeed()
+	if err := feed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">`,
+		`<dc:creator>Alan A. A. Donovan</dc:creator>`,
+		`<dc:identifier>urn:isbn:9780134190440</dc:identifier>`,
+		`<dcterms:modified>2012-12-21T08:30:15Z</dcterms:modified>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Encode() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+
+	got, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := feed.Entries[0].DublinCore; !reflect.DeepEqual(got.Entries[0].DublinCore, want) {
+		t.Errorf("Decode() dublin core = %+v, want %+v", got.Entries[0].DublinCore, want)
+	}
+}
+
+func TestDublinCoreRSSCreator(t *testing.T) {
+	feed := newCatalogueFeed()
+	feed.Entries[0].DublinCore = nil
+	feed.Entries = append(feed.Entries, feed.Entries[0])
+	feed.Entries[1].Author = NewPerson("Octo Cat", "octo@github.com", "")
+
+	out := &bytes.Buffer{}
+	if err := feed.EncodeRSS(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		"<dc:creator>Go Pher</dc:creator>\n    <item>",
+		"<guid isPermaLink=\"false\">tag:example.com,2012-12-21:catalogue.post-20121221083015</guid>\n      <pubDate>Fri, 21 Dec 2012 08:30:15 +0000</pubDate>\n      <dc:creator>Go Pher</dc:creator>",
+		`<author>octo@github.com (Octo Cat)</author>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("EncodeRSS() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "<dc:creator>Octo Cat</dc:creator>") {
+		t.Errorf("EncodeRSS() should not fall back to dc:creator for authors with email address")
+	}
+	if feed.Entries[0].DublinCore != nil || feed.DublinCore != nil {
+		t.Errorf("EncodeRSS() must not modify the feed")
+	}
+}
+
+func Test_checkDublinCore(t *testing.T) {
+	tests := []struct {
+		name    string
+		dc      *DublinCore
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &DublinCore{Creators: []string{"Go Pher"}, Modified: NewDate(time.Now())}, false},
+		{"empty creator", &DublinCore{Creators: []string{""}}, true},
+		{"empty subject", &DublinCore{Subjects: []string{""}}, true},
+		{"zero modified date", &DublinCore{Modified: &Date{}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDublinCore(tt.dc); (err != nil) != tt.wantErr {
+				t.Errorf("checkDublinCore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
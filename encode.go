@@ -0,0 +1,181 @@
+package atomfeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+)
+
+// DefaultIndent is the indentation of nested elements used by Encode.
+const DefaultIndent = "  "
+
+// EncodeOptions control the formatting of an encoded feed.
+// The zero value produces the same output as Encode.
+type EncodeOptions struct {
+	// Compact writes the feed without line breaks and indentation between elements.
+	Compact bool
+	// Indent is the indentation of nested elements, e.g. "\t". Defaults to DefaultIndent.
+	Indent string
+	// SelfClosing writes empty elements as <link href="…"/> instead of <link href="…"></link>.
+	SelfClosing bool
+	// SortAttributes writes namespace declarations first, followed by all other attributes in alphabetical order.
+	SortAttributes bool
+	// Report is called for every change made to the encoded feed, i.e. for every
+	// text, from which characters were removed, which are illegal in XML 1.0.
+	Report func(change string)
+}
+
+// EncodeWithOptions writes the XML encoding of Feed formatted according to o to the stream.
+//
+// Characters, which are illegal in XML 1.0 (like control characters within pasted content),
+// are removed from the output and reported to o.Report; Feed itself is not modified.
+// Raw XML fragments (ValueXML) must be well-formed, otherwise nothing is written and an error is returned.
+func (f *Feed) EncodeWithOptions(w io.Writer, o EncodeOptions) error {
+	clean, changes, err := f.clean()
+	if err != nil {
+		return err
+	}
+	if o.Report != nil {
+		for _, change := range changes {
+			o.Report(change)
+		}
+	}
+	header := []byte(xml.Header)
+	if clean.Stylesheet != nil {
+		header = append(header, clean.Stylesheet.procInst()...)
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if o.SelfClosing == false && o.SortAttributes == false {
+		return o.encoder(w).Encode(clean)
+	}
+	b := &bytes.Buffer{}
+	if err := o.encoder(b).Encode(clean); err != nil {
+		return err
+	}
+	out, err := o.rewriteTags(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func (o *EncodeOptions) encoder(w io.Writer) *xml.Encoder {
+	enc := xml.NewEncoder(w)
+	if o.Compact == false {
+		indent := o.Indent
+		if indent == "" {
+			indent = DefaultIndent
+		}
+		enc.Indent("", indent)
+	}
+	return enc
+}
+
+// rawContentElements are the Atom elements, whose raw XML content (ValueXML) is written as is.
+var rawContentElements = map[string]bool{"title": true, "subtitle": true, "rights": true, "summary": true, "content": true}
+
+// rewriteTags closes empty elements and sorts attributes of the encoded document data.
+// All other parts of the document are kept byte by byte, so that whitespace and
+// raw XML content are not changed. Tags within text constructs and content,
+// like xhtml, are never rewritten: <span class="icon"></span> is not <span class="icon"/> in HTML.
+func (o *EncodeOptions) rewriteTags(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false // formatting must not fail on raw content, which is not well-formed
+	out := &bytes.Buffer{}
+	var pending []byte // start tag, which is closed if it is directly followed by its end tag
+	depth := 0         // depth within a text construct or content element
+	for {
+		offset := d.InputOffset()
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw := data[offset:d.InputOffset()]
+		if pending != nil {
+			_, isEnd := t.(xml.EndElement)
+			switch {
+			case isEnd && len(raw) == 0: // end of an already self-closing element
+				out.Write(pending)
+				pending = nil
+				depth = 0
+				continue
+			case isEnd:
+				out.Write(pending[:len(pending)-1])
+				out.WriteString("/>")
+				pending = nil
+				depth = 0
+				continue
+			}
+			out.Write(pending)
+			pending = nil
+		}
+		if depth > 0 {
+			switch t.(type) {
+			case xml.StartElement:
+				depth++
+			case xml.EndElement:
+				depth--
+			}
+			out.Write(raw)
+			continue
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			if start.Name.Space == "" && rawContentElements[start.Name.Local] {
+				depth = 1
+			}
+			if o.SortAttributes {
+				raw = startTag(start, bytes.HasSuffix(raw, []byte("/>")))
+			}
+			if o.SelfClosing {
+				pending = raw
+				continue
+			}
+		}
+		out.Write(raw)
+	}
+	out.Write(pending)
+	return out.Bytes(), nil
+}
+
+// startTag encodes a start element with sorted attributes.
+func startTag(start xml.StartElement, selfClosing bool) []byte {
+	attrs := append([]xml.Attr{}, start.Attr...)
+	sort.SliceStable(attrs, func(i, j int) bool {
+		a, b := attrs[i].Name, attrs[j].Name
+		if isNamespaceDeclaration(a) != isNamespaceDeclaration(b) {
+			return isNamespaceDeclaration(a)
+		}
+		return qualifiedName(a) < qualifiedName(b)
+	})
+	b := &bytes.Buffer{}
+	b.WriteString("<" + qualifiedName(start.Name))
+	for _, attr := range attrs {
+		b.WriteString(" " + qualifiedName(attr.Name) + `="`)
+		xml.EscapeText(b, []byte(attr.Value))
+		b.WriteString(`"`)
+	}
+	if selfClosing {
+		b.WriteString("/")
+	}
+	b.WriteString(">")
+	return b.Bytes()
+}
+
+func isNamespaceDeclaration(name xml.Name) bool {
+	return name.Space == "xmlns" || (name.Space == "" && name.Local == "xmlns")
+}
+
+// qualifiedName returns the name of a raw token including its prefix.
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,79 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeedEncodeWithOptions(t *testing.T) {
+	feed, err := Decode(strings.NewReader(basicBlogFeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed.Entries = feed.Entries[:1]
+	feed.Entries[0].Content = &Content{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml"><pre>a
+  b</pre><br></br><span class="icon"></span><img src="a.png" alt="a"/>&#160;</div>`}
+	tests := []struct {
+		name    string
+		options EncodeOptions
+		want    []string
+	}{
+		{"default", EncodeOptions{}, []string{
+			"<feed xmlns=\"http://www.w3.org/2005/Atom\">\n  <id>",
+			`<link href="https://example.com" rel="alternate" type="text/html"></link>`,
+		}},
+		{"indent", EncodeOptions{Indent: "\t"}, []string{
+			"<feed xmlns=\"http://www.w3.org/2005/Atom\">\n\t<id>",
+			"\n\t<author>\n\t\t<name>Go Pher</name>",
+		}},
+		{"compact", EncodeOptions{Compact: true, Indent: "\t"}, []string{
+			`<feed xmlns="http://www.w3.org/2005/Atom"><id>tag:example.com,2012-12-21:blog</id><generator`,
+			"<pre>a\n  b</pre>",
+		}},
+		{"self-closing", EncodeOptions{SelfClosing: true}, []string{
+			`<link href="https://example.com" rel="alternate" type="text/html"/>`,
+			`<category term="tech"/>`,
+			`<br></br><span class="icon"></span><img src="a.png" alt="a"/>&#160;`, // xhtml is kept
+			"<title>Article 1</title>",
+		}},
+		{"sorted attributes", EncodeOptions{SortAttributes: true}, []string{
+			`<generator uri="https://github.com/denisbrodbeck/atomfeed" version="1.0">`,
+			`<img src="a.png" alt="a"/>`, // xhtml is kept
+			`<content type="xhtml">`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := feed.EncodeWithOptions(out, tt.options); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if strings.Contains(out.String(), want) == false {
+					t.Errorf("EncodeWithOptions() output is missing %q\n\ngot:\n%v", want, out)
+				}
+			}
+			got, err := Decode(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Entries[0].Categories, feed.Entries[0].Categories) || !reflect.DeepEqual(got.Links, feed.Links) {
+				t.Errorf("EncodeWithOptions() changed the feed: got %+v, want %+v", got.Links, feed.Links)
+			}
+		})
+	}
+}
+
+func Test_startTag(t *testing.T) {
+	out := &bytes.Buffer{}
+	feed := Feed{Namespace: nsAtom, Geo: NewGeoPoint(1, 2), CommonAttributes: &CommonAttributes{Lang: "en", Base: "https://example.com/"}}
+	if err := feed.EncodeWithOptions(out, EncodeOptions{SortAttributes: true, Compact: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:georss="http://www.georss.org/georss" xml:base="https://example.com/" xml:lang="en">`
+	if strings.Contains(out.String(), want) == false {
+		t.Errorf("EncodeWithOptions() = %v, want %v", out, want)
+	}
+}
@@ -109,6 +109,26 @@ func ExampleNewCategory() {
 func ExampleNewDate() {
 	now := time.Date(2017, time.December, 22, 8, 30, 15, 0, time.UTC)
 	date := atomfeed.NewDate(now)
-	fmt.Println(date.Value)
+	fmt.Println(date)
 	// Output: 2017-12-22T08:30:15Z
 }
+
+// Attach a machine-readable license to a feed and report the license of its entries:
+func ExampleNewLicense() {
+	feed := atomfeed.Feed{
+		Links:     []atomfeed.Link{atomfeed.NewLicense("https://creativecommons.org/licenses/by/4.0/")},
+		Copyright: atomfeed.NewCopyright("© 2017 Go Pher, some rights reserved"),
+		Entries: []atomfeed.Entry{
+			{ID: atomfeed.NewID("tag:example.com,2005-07-18:blog.post-1")},
+			{ID: atomfeed.NewID("tag:example.com,2005-07-18:blog.post-2"), Links: []atomfeed.Link{atomfeed.NewLicense("https://creativecommons.org/licenses/by-sa/4.0/")}},
+		},
+	}
+	for _, entry := range feed.Entries {
+		for _, license := range entry.Licenses(&feed) {
+			fmt.Println(license.Href)
+		}
+	}
+	// Output:
+	// https://creativecommons.org/licenses/by/4.0/
+	// https://creativecommons.org/licenses/by-sa/4.0/
+}
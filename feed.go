@@ -2,7 +2,6 @@ package atomfeed // import "github.com/denisbrodbeck/atomfeed"
 
 import (
 	"encoding/base64"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"strings"
@@ -10,11 +9,11 @@ import (
 )
 
 // Encode writes the XML encoding of Feed to the stream.
+// The xml-stylesheet processing instruction of Feed.Stylesheet is written after the XML declaration.
+// Characters, which are illegal in XML 1.0, are removed from the output.
+// Use EncodeWithOptions for compact output, other formatting options or a report of removed characters.
 func (f *Feed) Encode(w io.Writer) error {
-	enc := xml.NewEncoder(w)
-	enc.Indent("", "  ")
-	w.Write([]byte(xml.Header))
-	return enc.Encode(f)
+	return f.EncodeWithOptions(w, EncodeOptions{})
 }
 
 // NewFeed creates a basic atom:feed element suitable for e.g. a blog.
@@ -25,7 +24,7 @@ func NewFeed(id ID, author *Person, title, subtitle, baseURL, feedURL string, up
 		Value:   "atomfeed package",
 	}
 	return Feed{
-		Namespace: "http://www.w3.org/2005/Atom",
+		Namespace: nsAtom,
 		ID:        id,
 		Title:     &TextConstruct{Value: title},
 		Subtitle:  &TextConstruct{Value: subtitle},
@@ -87,6 +86,21 @@ func NewContent(contentType, source string, value []byte) *Content {
 	if source == "" && (value == nil || len(value) == 0) {
 		return nil
 	}
+	switch {
+	case contentType == "xhtml":
+		// add the mandatory div wrapper and fix malformed markup where possible
+		return &Content{Type: contentType, Source: source, ValueXML: WrapXHTML(string(value))}
+	case isXMLContentType(contentType):
+		return &Content{Type: contentType, Source: source, ValueXML: string(value)}
+	case isTextContentType(contentType):
+		return &Content{Type: contentType, Source: source, Value: string(value)}
+	}
+	// all other types MUST be base64 encoded
+	return &Content{Type: contentType, Source: source, Value: base64.StdEncoding.EncodeToString(value), base64Encoded: true}
+}
+
+// isXMLContentType reports whether content of the given type is embedded as XML.
+func isXMLContentType(contentType string) bool {
 	switch {
 	case contentType == "xhtml",
 		contentType == "text/xml", // https://tools.ietf.org/html/rfc3023#section-3
@@ -96,23 +110,27 @@ func NewContent(contentType, source string, value []byte) *Content {
 		contentType == "application/xml-dtd",
 		strings.HasSuffix(strings.ToLower(contentType), "+xml"),
 		strings.HasSuffix(strings.ToLower(contentType), "/xml"):
-		return &Content{Type: contentType, Source: source, ValueXML: string(value)}
-	case contentType == "",
-		contentType == "text",
-		contentType == "html",
-		strings.HasPrefix(strings.ToLower(contentType), "text/"):
-		return &Content{Type: contentType, Source: source, Value: string(value)}
+		return true
 	}
-	// all other types MUST be base64 encoded
-	return &Content{Type: contentType, Source: source, Value: base64.StdEncoding.EncodeToString(value), base64Encoded: true}
+	return false
 }
 
-// NewDate returns an atom:date element with valid RFC3339 time data.
+// isTextContentType reports whether content of the given type is embedded as escaped text.
+func isTextContentType(contentType string) bool {
+	return contentType == "" ||
+		contentType == "text" ||
+		contentType == "html" ||
+		strings.HasPrefix(strings.ToLower(contentType), "text/")
+}
+
+// NewDate returns an atom:date element, which is encoded as valid RFC3339 time data.
 func NewDate(t time.Time) *Date {
 	if t.IsZero() {
 		return nil
 	}
-	return &Date{Value: t.Format(time.RFC3339)}
+	d := &Date{}
+	d.Set(t)
+	return d
 }
 
 // NewPerson returns an atom:person element.
@@ -125,6 +143,22 @@ func NewCategory(category string) *Category {
 	return &Category{Term: category}
 }
 
+// NewCopyright returns an atom:rights element, which conveys human-readable information about rights.
+//
+// https://tools.ietf.org/html/rfc4287#section-4.2.10
+func NewCopyright(rights string) *TextConstruct {
+	return &TextConstruct{Value: rights}
+}
+
+// NewLicense returns an atom:link element with the "license" relation,
+// which references a machine-readable license like a Creative Commons license.
+// Use LicenseUnspecified to prevent an entry from inheriting the license of its feed.
+//
+// https://tools.ietf.org/html/rfc4946
+func NewLicense(iri string) Link {
+	return Link{Rel: "license", Href: iri}
+}
+
 // NewEntry creates a basic atom:entry suitable for e.g. a blog.
 func NewEntry(id ID, title, permalink string, author *Person, updated, published time.Time, categories []string, summary, content []byte) Entry {
 	return Entry{
@@ -154,18 +188,27 @@ func termsToCategories(categories []string) []Category {
 	return cat
 }
 
-func (e *Entry) String() string {
-	title := ""
-	if e.Title != nil {
-		title = e.Title.Value
+// textValue returns the value of an optional text construct.
+// Markup of XHTML text constructs is removed.
+func textValue(t *TextConstruct) string {
+	if t == nil {
+		return ""
+	}
+	if t.Type == "xhtml" {
+		return htmlToText(t.ValueXML)
 	}
+	return t.Value
+}
+
+func (e *Entry) String() string {
+	title := textValue(e.Title)
 	updated := ""
 	if e.Updated != nil {
-		updated = e.Updated.Value
+		updated = e.Updated.String()
 	}
 	published := ""
 	if e.Published != nil {
-		published = e.Published.Value
+		published = e.Published.String()
 	}
 	author := ""
 	if e.Author != nil {
@@ -0,0 +1,83 @@
+package atomfeed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Variant is a pre-compressed variant of a feed file, which web servers and CDNs
+// serve to clients accepting its content encoding.
+type Variant struct {
+	// Ext is appended to the name of the feed file, e.g. ".gz" results in "feed.xml.gz".
+	Ext string
+	// NewWriter returns a writer, which compresses all data written to it into w.
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// Gzip is the gzip compressed variant of a feed file.
+var Gzip = Variant{
+	Ext: ".gz",
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, gzip.BestCompression)
+	},
+}
+
+// WriteFiles writes the encoding of the feed with o to the file name and its compressed
+// variants to the file name with the extension of the variant, e.g. feed.xml, feed.xml.gz and feed.xml.br
+// with the variants Gzip and Brotli.
+// Files are replaced atomically, so that web servers never serve partially written files.
+func (f *Feed) WriteFiles(name string, o EncodeOptions, variants ...Variant) error {
+	data := &bytes.Buffer{}
+	if err := f.EncodeWithOptions(data, o); err != nil {
+		return err
+	}
+	type file struct {
+		name string
+		data []byte
+	}
+	files := []file{{name, data.Bytes()}}
+	for _, v := range variants {
+		compressed := &bytes.Buffer{}
+		w, err := v.NewWriter(compressed)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data.Bytes()); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		files = append(files, file{name + v.Ext, compressed.Bytes()})
+	}
+	for _, file := range files {
+		if err := writeFile(file.name, file.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces a file atomically by renaming a temporary file.
+func writeFile(name string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
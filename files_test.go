@@ -0,0 +1,61 @@
+package atomfeed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type nopCompressor struct {
+	io.Writer
+}
+
+func (nopCompressor) Close() error { return nil }
+
+func TestFeedWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomfeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	feed := Feed{Namespace: nsAtom, ID: NewID("tag:example.com,2005:blog"), Title: &TextConstruct{Value: "Blog"}}
+	identity := Variant{Ext: ".id", NewWriter: func(w io.Writer) (io.WriteCloser, error) { return nopCompressor{w}, nil }}
+	name := filepath.Join(dir, "feed.xml")
+	if err := feed.WriteFiles(name, EncodeOptions{Compact: true}, Gzip, Brotli, identity); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &bytes.Buffer{}
+	feed.EncodeWithOptions(want, EncodeOptions{Compact: true})
+	if got, _ := ioutil.ReadFile(name); !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("feed.xml = %s, want %s", got, want)
+	}
+	if got, _ := ioutil.ReadFile(name + ".id"); !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("feed.xml.id = %s, want %s", got, want)
+	}
+	compressed, err := os.Open(name + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer compressed.Close()
+	r, err := gzip.NewReader(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ioutil.ReadAll(r); !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("feed.xml.gz = %s, want %s", got, want)
+	}
+	if info, err := os.Stat(name + ".br"); err != nil || info.Size() == 0 {
+		t.Errorf("feed.xml.br is missing: %v", err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 4 {
+		t.Errorf("WriteFiles() left %v files, want 4", len(files))
+	}
+	if info, _ := os.Stat(name); info.Mode().Perm() != 0644 {
+		t.Errorf("feed.xml mode = %v, want 0644", info.Mode())
+	}
+}
@@ -0,0 +1,303 @@
+package atomfeed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Geo contains the GeoRSS elements of a feed or an entry, which describe its location.
+// A location is either expressed by GeoRSS Simple (Point, Line, Polygon, Box)
+// or by GeoRSS GML (Where), but only one shape may be used at a time.
+//  http://www.georss.org/simple.html
+//  http://www.georss.org/gml.html
+type Geo struct {
+	Point       *GeoPoint   `xml:"georss:point"`
+	Line        *GeoLine    `xml:"georss:line"`
+	Polygon     *GeoPolygon `xml:"georss:polygon"`
+	Box         *GeoBox     `xml:"georss:box"`
+	Where       *GeoWhere   `xml:"georss:where"`
+	FeatureName string      `xml:"georss:featureName,omitempty"`
+	// Elevation is the height above sea level in meters.
+	Elevation float64 `xml:"georss:elev,omitempty"`
+}
+
+// GeoWhere is a georss:where element, which contains a location in GML encoding.
+//  http://www.georss.org/gml.html
+type GeoWhere struct {
+	Point    *GeoPoint    `xml:"gml:Point>gml:pos"`
+	Line     *GeoLine     `xml:"gml:LineString>gml:posList"`
+	Polygon  *GeoPolygon  `xml:"gml:Polygon>gml:exterior>gml:LinearRing>gml:posList"`
+	Envelope *GeoEnvelope `xml:"gml:Envelope"`
+}
+
+// GeoEnvelope is a gml:Envelope element and is the GML encoding of a GeoBox.
+type GeoEnvelope struct {
+	Lower GeoPoint `xml:"gml:lowerCorner"`
+	Upper GeoPoint `xml:"gml:upperCorner"`
+}
+
+// GeoPoint is a single WGS84 coordinate, encoded as latitude and longitude separated by whitespace.
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// GeoLine is a list of at least two coordinates.
+type GeoLine []GeoPoint
+
+// GeoPolygon is a closed list of at least four coordinates, whose first and last coordinates are identical.
+type GeoPolygon []GeoPoint
+
+// GeoBox is a bounding box, which is defined by its lower left and upper right corners.
+type GeoBox struct {
+	Lower GeoPoint
+	Upper GeoPoint
+}
+
+// NewGeoPoint returns a location with a single coordinate.
+func NewGeoPoint(lat, lon float64) *Geo {
+	return &Geo{Point: &GeoPoint{Lat: lat, Lon: lon}}
+}
+
+// NewGeoBox returns a bounding box by its lower left and upper right corners.
+func NewGeoBox(lowerLat, lowerLon, upperLat, upperLon float64) GeoBox {
+	return GeoBox{Lower: GeoPoint{Lat: lowerLat, Lon: lowerLon}, Upper: GeoPoint{Lat: upperLat, Lon: upperLon}}
+}
+
+// MarshalText encodes the coordinate as "lat lon".
+func (p GeoPoint) MarshalText() ([]byte, error) {
+	return []byte(formatGeoPoints([]GeoPoint{p})), nil
+}
+
+// UnmarshalText decodes a coordinate from "lat lon".
+func (p *GeoPoint) UnmarshalText(text []byte) error {
+	points, err := parseGeoPoints(string(text))
+	if err != nil {
+		return err
+	}
+	if len(points) != 1 {
+		return fmt.Errorf("georss: point must have exactly one coordinate, got %d", len(points))
+	}
+	*p = points[0]
+	return nil
+}
+
+// MarshalText encodes the coordinates as "lat lon lat lon …".
+func (l GeoLine) MarshalText() ([]byte, error) {
+	return []byte(formatGeoPoints(l)), nil
+}
+
+// UnmarshalText decodes coordinates from "lat lon lat lon …".
+func (l *GeoLine) UnmarshalText(text []byte) error {
+	points, err := parseGeoPoints(string(text))
+	*l = points
+	return err
+}
+
+// MarshalText encodes the coordinates as "lat lon lat lon …".
+func (p GeoPolygon) MarshalText() ([]byte, error) {
+	return []byte(formatGeoPoints(p)), nil
+}
+
+// UnmarshalText decodes coordinates from "lat lon lat lon …".
+func (p *GeoPolygon) UnmarshalText(text []byte) error {
+	points, err := parseGeoPoints(string(text))
+	*p = points
+	return err
+}
+
+// MarshalText encodes the box as "lowerLat lowerLon upperLat upperLon".
+func (b GeoBox) MarshalText() ([]byte, error) {
+	return []byte(formatGeoPoints([]GeoPoint{b.Lower, b.Upper})), nil
+}
+
+// UnmarshalText decodes a box from "lowerLat lowerLon upperLat upperLon".
+func (b *GeoBox) UnmarshalText(text []byte) error {
+	points, err := parseGeoPoints(string(text))
+	if err != nil {
+		return err
+	}
+	if len(points) != 2 {
+		return fmt.Errorf("georss: box must have exactly two coordinates, got %d", len(points))
+	}
+	b.Lower, b.Upper = points[0], points[1]
+	return nil
+}
+
+// Contains reports whether the coordinate lies within the box.
+// Boxes spanning the antimeridian (lower longitude greater than upper longitude) are supported.
+func (b GeoBox) Contains(p GeoPoint) bool {
+	if p.Lat < b.Lower.Lat || p.Lat > b.Upper.Lat {
+		return false
+	}
+	if b.Lower.Lon <= b.Upper.Lon {
+		return p.Lon >= b.Lower.Lon && p.Lon <= b.Upper.Lon
+	}
+	return p.Lon >= b.Lower.Lon || p.Lon <= b.Upper.Lon
+}
+
+// Points returns all coordinates of the location regardless of its shape and encoding.
+func (g *Geo) Points() []GeoPoint {
+	if g == nil {
+		return nil
+	}
+	points := []GeoPoint{}
+	for _, shape := range g.shapes() {
+		points = append(points, shape...)
+	}
+	return points
+}
+
+// shapes returns the coordinates of every shape set on the location.
+func (g *Geo) shapes() [][]GeoPoint {
+	shapes := [][]GeoPoint{}
+	if g.Point != nil {
+		shapes = append(shapes, []GeoPoint{*g.Point})
+	}
+	if g.Line != nil {
+		shapes = append(shapes, *g.Line)
+	}
+	if g.Polygon != nil {
+		shapes = append(shapes, *g.Polygon)
+	}
+	if g.Box != nil {
+		shapes = append(shapes, []GeoPoint{g.Box.Lower, g.Box.Upper})
+	}
+	if w := g.Where; w != nil {
+		if w.Point != nil {
+			shapes = append(shapes, []GeoPoint{*w.Point})
+		}
+		if w.Line != nil {
+			shapes = append(shapes, *w.Line)
+		}
+		if w.Polygon != nil {
+			shapes = append(shapes, *w.Polygon)
+		}
+		if w.Envelope != nil {
+			shapes = append(shapes, []GeoPoint{w.Envelope.Lower, w.Envelope.Upper})
+		}
+	}
+	return shapes
+}
+
+// Within returns a copy of the feed, which only contains entries
+// whose location lies completely within the bounding box.
+// Entries without location are dropped.
+func (f *Feed) Within(box GeoBox) Feed {
+	feed := *f
+	feed.Entries = []Entry{}
+	for _, e := range f.Entries {
+		points := e.Geo.Points()
+		if len(points) == 0 {
+			continue
+		}
+		within := true
+		for _, p := range points {
+			if box.Contains(p) == false {
+				within = false
+				break
+			}
+		}
+		if within {
+			feed.Entries = append(feed.Entries, e)
+		}
+	}
+	return feed
+}
+
+func formatGeoPoints(points []GeoPoint) string {
+	values := []string{}
+	for _, p := range points {
+		values = append(values, strconv.FormatFloat(p.Lat, 'f', -1, 64), strconv.FormatFloat(p.Lon, 'f', -1, 64))
+	}
+	return strings.Join(values, " ")
+}
+
+func parseGeoPoints(text string) ([]GeoPoint, error) {
+	fields := strings.Fields(text)
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("georss: odd number of values in coordinate list %q", text)
+	}
+	points := []GeoPoint{}
+	for i := 0; i < len(fields); i += 2 {
+		lat, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("georss: invalid latitude: %v", err)
+		}
+		lon, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("georss: invalid longitude: %v", err)
+		}
+		points = append(points, GeoPoint{Lat: lat, Lon: lon})
+	}
+	return points, nil
+}
+
+func usesGeoRSS(f *Feed) bool {
+	if f.Geo != nil {
+		return true
+	}
+	for _, e := range f.Entries {
+		if e.Geo != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func usesGML(f *Feed) bool {
+	if f.Geo != nil && f.Geo.Where != nil {
+		return true
+	}
+	for _, e := range f.Entries {
+		if e.Geo != nil && e.Geo.Where != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func checkGeo(g *Geo) error {
+	if g == nil {
+		return nil
+	}
+	shapes := g.shapes()
+	if len(shapes) != 1 {
+		return fmt.Errorf("georss: location must have exactly one shape, got %d", len(shapes))
+	}
+	for _, p := range shapes[0] {
+		if err := checkGeoPoint(p); err != nil {
+			return err
+		}
+	}
+	switch {
+	case g.Line != nil || (g.Where != nil && g.Where.Line != nil):
+		if len(shapes[0]) < 2 {
+			return fmt.Errorf("georss: line must have at least two coordinates")
+		}
+	case g.Polygon != nil || (g.Where != nil && g.Where.Polygon != nil):
+		polygon := shapes[0]
+		if len(polygon) < 4 {
+			return fmt.Errorf("georss: polygon must have at least four coordinates")
+		}
+		if polygon[0] != polygon[len(polygon)-1] {
+			return fmt.Errorf("georss: polygon must be closed (first and last coordinate must be identical)")
+		}
+	case g.Box != nil || (g.Where != nil && g.Where.Envelope != nil):
+		if shapes[0][0].Lat > shapes[0][1].Lat {
+			return fmt.Errorf("georss: box must have its lower corner below its upper corner")
+		}
+	}
+	return nil
+}
+
+func checkGeoPoint(p GeoPoint) error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("georss: latitude %v out of range [-90, 90]", p.Lat)
+	}
+	if p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("georss: longitude %v out of range [-180, 180]", p.Lon)
+	}
+	return nil
+}
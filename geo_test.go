@@ -0,0 +1,120 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeoEncode(t *testing.T) {
+	now := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "reports")
+	author := NewPerson("Go Pher", "", "")
+	berlin := NewEntry(NewEntryID(feedID, now), "Berlin", "https://example.com/berlin", author, now, now, nil, nil, []byte("Berlin"))
+	berlin.Geo = NewGeoPoint(52.52, 13.405)
+	spree := NewEntry(NewEntryID(feedID, now.Add(time.Hour)), "Spree", "https://example.com/spree", author, now, now, nil, nil, []byte("Spree"))
+	spree.Geo = &Geo{Where: &GeoWhere{Line: &GeoLine{{52.5, 13.3}, {52.51, 13.45}}}}
+	feed := NewFeed(feedID, author, "Reports", "", "https://example.com", "https://example.com/feed.atom", now, []Entry{berlin, spree})
+	if err := feed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">`,
+		`<georss:point>52.52 13.405</georss:point>`,
+		"<georss:where>\n      <gml:LineString>\n        <gml:posList>52.5 13.3 52.51 13.45</gml:posList>\n      </gml:LineString>\n    </georss:where>",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Encode() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+
+	got, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range feed.Entries {
+		if !reflect.DeepEqual(got.Entries[i].Geo, feed.Entries[i].Geo) {
+			t.Errorf("Decode() geo = %+v, want %+v", got.Entries[i].Geo, feed.Entries[i].Geo)
+		}
+	}
+}
+
+func TestFeedWithin(t *testing.T) {
+	feed := Feed{
+		Title: &TextConstruct{Value: "Reports"},
+		Entries: []Entry{
+			{ID: NewID("berlin"), Geo: NewGeoPoint(52.52, 13.405)},
+			{ID: NewID("paris"), Geo: NewGeoPoint(48.8566, 2.3522)},
+			{ID: NewID("nowhere")},
+			{ID: NewID("havel"), Geo: &Geo{Line: &GeoLine{{52.4, 13.1}, {52.6, 13.2}}}},
+			{ID: NewID("germany"), Geo: &Geo{Box: &GeoBox{Lower: GeoPoint{47.3, 5.9}, Upper: GeoPoint{55.1, 15.0}}}},
+		},
+	}
+	got := feed.Within(NewGeoBox(52.3, 13.0, 52.7, 13.8))
+	ids := []string{}
+	for _, e := range got.Entries {
+		ids = append(ids, e.ID.Value)
+	}
+	if want := []string{"berlin", "havel"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Within() = %v, want %v", ids, want)
+	}
+	if got.Title != feed.Title || len(feed.Entries) != 5 {
+		t.Error("Within() must keep feed metadata and must not modify the original feed")
+	}
+}
+
+func TestGeoBoxContains(t *testing.T) {
+	pacific := NewGeoBox(-50, 170, 10, -170) // spans the antimeridian
+	if !pacific.Contains(GeoPoint{0, 179}) || !pacific.Contains(GeoPoint{0, -179}) {
+		t.Error("Contains() should support boxes across the antimeridian")
+	}
+	if pacific.Contains(GeoPoint{0, 0}) {
+		t.Error("Contains() returned true for a coordinate outside the box")
+	}
+}
+
+func Test_checkGeo(t *testing.T) {
+	tests := []struct {
+		name    string
+		geo     *Geo
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"point", NewGeoPoint(45.256, -71.92), false},
+		{"latitude out of range", NewGeoPoint(91, 0), true},
+		{"longitude out of range", NewGeoPoint(0, -180.5), true},
+		{"no shape", &Geo{FeatureName: "Berlin"}, true},
+		{"two shapes", &Geo{Point: &GeoPoint{1, 1}, Where: &GeoWhere{Point: &GeoPoint{1, 1}}}, true},
+		{"short line", &Geo{Line: &GeoLine{{1, 1}}}, true},
+		{"open polygon", &Geo{Polygon: &GeoPolygon{{1, 1}, {1, 2}, {2, 2}, {2, 1}}}, true},
+		{"closed polygon", &Geo{Polygon: &GeoPolygon{{1, 1}, {1, 2}, {2, 2}, {1, 1}}}, false},
+		{"upside down box", &Geo{Where: &GeoWhere{Envelope: &GeoEnvelope{Lower: GeoPoint{10, 0}, Upper: GeoPoint{0, 10}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkGeo(tt.geo); (err != nil) != tt.wantErr {
+				t.Errorf("checkGeo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeoPointUnmarshalText(t *testing.T) {
+	p := GeoPoint{}
+	if err := p.UnmarshalText([]byte("  45.256\n-71.92 ")); err != nil || p != (GeoPoint{45.256, -71.92}) {
+		t.Errorf("UnmarshalText() = %v, %v", p, err)
+	}
+	if err := p.UnmarshalText([]byte("45.256")); err == nil {
+		t.Error("expected an error on missing longitude, got none")
+	}
+	if err := p.UnmarshalText([]byte("north east")); err == nil {
+		t.Error("expected an error on invalid numbers, got none")
+	}
+}
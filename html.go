@@ -0,0 +1,516 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	nsSVG    = "http://www.w3.org/2000/svg"
+	nsMathML = "http://www.w3.org/1998/Math/MathML"
+)
+
+// htmlTokens parses HTML leniently – like browsers do – into a list of balanced XML tokens.
+// Any input is accepted: a "<" or "&", which doesn't start a tag or a character reference, is text,
+// attribute values may be unquoted, end tags are implied (e.g. of p and li elements),
+// unclosed elements are closed and stray end tags are ignored.
+// Doctypes and processing instructions are dropped.
+//  https://html.spec.whatwg.org/multipage/parsing.html
+func htmlTokens(html string) []xml.Token {
+	z := &htmlTokenizer{s: html}
+	b := &htmlBuilder{}
+	for {
+		t, ok := z.next()
+		if ok == false {
+			break
+		}
+		switch t := t.(type) {
+		case htmlTag:
+			if t.end {
+				b.end(t)
+				continue
+			}
+			if b.start(t) && rawTextElements[strings.ToLower(t.name)] {
+				z.rawText = strings.ToLower(t.name)
+			}
+		case xml.CharData:
+			b.text(t)
+		case xml.Comment:
+			b.tokens = append(b.tokens, t)
+		}
+	}
+	b.closeAll()
+	return b.tokens
+}
+
+// htmlTag is a start or end tag as written in the source.
+type htmlTag struct {
+	name        string
+	attrs       []htmlAttr
+	end         bool
+	selfClosing bool
+}
+
+type htmlAttr struct {
+	name, value string
+	raw         string // value as written in the source
+	pos         int    // offset of raw in the source, zero for attributes without value
+	quoted      bool
+}
+
+// rawTextElements contain text only: markup within them is not parsed.
+// Character references are decoded within title and textarea elements.
+var rawTextElements = toSet([]string{"script", "style", "title", "textarea", "xmp", "iframe", "noembed", "noframes"})
+
+// htmlTokenizer splits HTML into tags, text and comments.
+type htmlTokenizer struct {
+	s       string
+	pos     int
+	rawText string // name of the open raw text element, whose content is read as text
+}
+
+// next returns the next htmlTag, xml.CharData or xml.Comment and false at the end of the input.
+func (z *htmlTokenizer) next() (interface{}, bool) {
+	if z.pos >= len(z.s) {
+		return nil, false
+	}
+	if z.rawText != "" {
+		name := z.rawText
+		z.rawText = ""
+		end := indexFold(z.s[z.pos:], "</"+name)
+		if end < 0 {
+			end = len(z.s) - z.pos
+		}
+		text := z.s[z.pos : z.pos+end]
+		z.pos += end
+		if name == "title" || name == "textarea" {
+			text = decodeEntities(text)
+		}
+		if text != "" {
+			return xml.CharData(text), true
+		}
+		return z.next()
+	}
+	rest := z.s[z.pos:]
+	if rest[0] != '<' {
+		end := strings.IndexByte(rest[1:], '<') + 1
+		if end == 0 {
+			end = len(rest)
+		}
+		z.pos += end
+		return xml.CharData(decodeEntities(rest[:end])), true
+	}
+	switch {
+	case strings.HasPrefix(rest, "<!--"):
+		end := strings.Index(rest[4:], "-->")
+		if end < 0 {
+			z.pos = len(z.s)
+			return xml.Comment(rest[4:]), true
+		}
+		z.pos += 4 + end + 3
+		return xml.Comment(rest[4 : 4+end]), true
+	case strings.HasPrefix(rest, "<![CDATA["):
+		end := strings.Index(rest, "]]>")
+		if end < 0 {
+			z.pos = len(z.s)
+			return xml.CharData(rest[9:]), true
+		}
+		z.pos += end + 3
+		return xml.CharData(rest[9:end]), true
+	case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"), strings.HasPrefix(rest, "</") && len(rest) > 2 && isASCIILetter(rest[2]) == false:
+		// doctype, processing instruction or bogus comment
+		z.skipPast('>')
+		return z.next()
+	case strings.HasPrefix(rest, "</"):
+		z.pos += 2
+		t := htmlTag{name: z.tagName(), end: true}
+		z.skipPast('>')
+		return t, true
+	case len(rest) > 1 && isASCIILetter(rest[1]):
+		z.pos++
+		return z.startTag(), true
+	}
+	z.pos++
+	return xml.CharData("<"), true
+}
+
+// startTag reads a start tag after its "<".
+func (z *htmlTokenizer) startTag() htmlTag {
+	t := htmlTag{name: z.tagName()}
+	for {
+		z.skipSpace()
+		if z.pos >= len(z.s) {
+			return t
+		}
+		switch {
+		case z.s[z.pos] == '>':
+			z.pos++
+			return t
+		case strings.HasPrefix(z.s[z.pos:], "/>"):
+			z.pos += 2
+			t.selfClosing = true
+			return t
+		case z.s[z.pos] == '/':
+			z.pos++
+			continue
+		}
+		start := z.pos
+		for z.pos < len(z.s) && isSpace(z.s[z.pos]) == false && strings.IndexByte("/>=", z.s[z.pos]) < 0 {
+			z.pos++
+		}
+		if z.pos == start { // attribute name starting with "="
+			z.pos++
+		}
+		attr := htmlAttr{name: z.s[start:z.pos]}
+		attr.value = attr.name // boolean attribute
+		z.skipSpace()
+		if z.pos < len(z.s) && z.s[z.pos] == '=' {
+			z.pos++
+			z.skipSpace()
+			attr.quoted = z.pos < len(z.s) && (z.s[z.pos] == '"' || z.s[z.pos] == '\'')
+			attr.pos = z.pos
+			if attr.quoted {
+				attr.pos++
+			}
+			attr.raw = z.attrValue()
+			attr.value = decodeEntities(attr.raw)
+		}
+		t.attrs = append(t.attrs, attr)
+	}
+}
+
+// attrValue reads a quoted or unquoted attribute value.
+func (z *htmlTokenizer) attrValue() string {
+	if z.pos >= len(z.s) {
+		return ""
+	}
+	if quote := z.s[z.pos]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(z.s[z.pos+1:], quote)
+		if end < 0 {
+			value := z.s[z.pos+1:]
+			z.pos = len(z.s)
+			return value
+		}
+		value := z.s[z.pos+1 : z.pos+1+end]
+		z.pos += end + 2
+		return value
+	}
+	start := z.pos
+	for z.pos < len(z.s) && isSpace(z.s[z.pos]) == false && z.s[z.pos] != '>' {
+		z.pos++
+	}
+	return z.s[start:z.pos]
+}
+
+func (z *htmlTokenizer) tagName() string {
+	start := z.pos
+	for z.pos < len(z.s) && isSpace(z.s[z.pos]) == false && z.s[z.pos] != '/' && z.s[z.pos] != '>' {
+		z.pos++
+	}
+	return z.s[start:z.pos]
+}
+
+func (z *htmlTokenizer) skipSpace() {
+	for z.pos < len(z.s) && isSpace(z.s[z.pos]) {
+		z.pos++
+	}
+}
+
+func (z *htmlTokenizer) skipPast(c byte) {
+	if end := strings.IndexByte(z.s[z.pos:], c); end >= 0 {
+		z.pos += end + 1
+	} else {
+		z.pos = len(z.s)
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// indexFold returns the index of the first ASCII case-insensitive occurrence of substr in s or -1.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// decodeEntities replaces character references with the characters they represent.
+// Unknown references and ampersands without reference are kept as text.
+func decodeEntities(s string) string {
+	if strings.IndexByte(s, '&') < 0 {
+		return s
+	}
+	b := &strings.Builder{}
+	for {
+		i := strings.IndexByte(s, '&')
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		r, size := characterReference(s)
+		if size == 0 {
+			b.WriteByte('&')
+			s = s[1:]
+			continue
+		}
+		b.WriteString(r)
+		s = s[size:]
+	}
+}
+
+// characterReference decodes the character reference at the start of s
+// and returns its replacement and its length, which is zero for no reference.
+func characterReference(s string) (string, int) {
+	if strings.HasPrefix(s, "&#") {
+		i, base, digits := 2, 10, "0123456789"
+		if len(s) > 2 && (s[2] == 'x' || s[2] == 'X') {
+			i, base, digits = 3, 16, "0123456789abcdefABCDEF"
+		}
+		start := i
+		for i < len(s) && strings.IndexByte(digits, s[i]) >= 0 {
+			i++
+		}
+		if i == start {
+			return "", 0
+		}
+		n, err := strconv.ParseUint(s[start:i], base, 32)
+		if i < len(s) && s[i] == ';' {
+			i++
+		}
+		r := rune(n)
+		if err != nil || r == 0 || r > utf8.MaxRune || (r >= 0xD800 && r <= 0xDFFF) {
+			r = utf8.RuneError
+		}
+		return string(r), i
+	}
+	i := 1
+	for i < len(s) && (isASCIILetter(s[i]) || '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	if i < len(s) && s[i] == ';' {
+		if r, ok := htmlEntity(s[1:i]); ok {
+			return r, i + 1
+		}
+	}
+	return "", 0
+}
+
+func htmlEntity(name string) (string, bool) {
+	switch name {
+	case "amp":
+		return "&", true
+	case "lt":
+		return "<", true
+	case "gt":
+		return ">", true
+	case "quot":
+		return `"`, true
+	case "apos":
+		return "'", true
+	}
+	r, ok := xml.HTMLEntity[name]
+	return r, ok
+}
+
+// voidElements can't have any content and are written as empty-element tags.
+var voidElements = toSet([]string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr"})
+
+// closesParagraph lists the elements, whose start tag implies the end of an open p element.
+var closesParagraph = toSet([]string{
+	"address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset", "figcaption", "figure",
+	"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav", "ol",
+	"p", "pre", "section", "summary", "table", "ul",
+})
+
+// scopeBoundaries stop the search for an open element, whose end is implied.
+var scopeBoundaries = toSet([]string{"applet", "button", "caption", "html", "marquee", "object", "table", "td", "template", "th"})
+
+// htmlBuilder balances tags into a list of XML tokens and resolves namespaces.
+type htmlBuilder struct {
+	tokens []xml.Token
+	open   []htmlElement
+}
+
+type htmlElement struct {
+	name       xml.Name
+	tag        string            // lowercased tag name
+	namespaces map[string]string // prefix → namespace URI in scope, "" is the default namespace
+}
+
+// start appends a start element and reports whether the element was left open.
+func (b *htmlBuilder) start(t htmlTag) bool {
+	tag := strings.ToLower(t.name)
+	if b.foreign() == false {
+		b.implyEndTags(tag)
+	}
+	namespaces := map[string]string{}
+	if len(b.open) > 0 {
+		namespaces = b.open[len(b.open)-1].namespaces
+	}
+	for _, attr := range t.attrs {
+		if attr.name == "xmlns" || strings.HasPrefix(attr.name, "xmlns:") {
+			scoped := map[string]string{}
+			for prefix, uri := range namespaces {
+				scoped[prefix] = uri
+			}
+			namespaces = scoped
+			break
+		}
+	}
+	for _, attr := range t.attrs {
+		if attr.name == "xmlns" {
+			namespaces[""] = attr.value
+		} else if strings.HasPrefix(attr.name, "xmlns:") {
+			namespaces[attr.name[len("xmlns:"):]] = attr.value
+		}
+	}
+
+	start := xml.StartElement{Name: resolveName(t.name, namespaces, true)}
+	if start.Name.Space == "" && (tag == "svg" || tag == "math") {
+		start.Name.Space = map[string]string{"svg": nsSVG, "math": nsMathML}[tag]
+		namespaces = map[string]string{"": start.Name.Space}
+	}
+	for _, attr := range t.attrs {
+		name := xml.Name{Local: attr.name}
+		switch {
+		case attr.name == "xmlns":
+		case strings.HasPrefix(attr.name, "xmlns:"):
+			name = xml.Name{Space: "xmlns", Local: attr.name[len("xmlns:"):]}
+		default:
+			name = resolveName(attr.name, namespaces, false)
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: name, Value: attr.value})
+	}
+	b.tokens = append(b.tokens, start)
+	html := start.Name.Space == "" || start.Name.Space == nsXHTML
+	if t.selfClosing || (html && voidElements[tag]) {
+		b.tokens = append(b.tokens, xml.EndElement{Name: start.Name})
+		return false
+	}
+	b.open = append(b.open, htmlElement{name: start.Name, tag: tag, namespaces: namespaces})
+	return true
+}
+
+// resolveName resolves the prefix of an element or attribute name like the encoding/xml package:
+// undeclared prefixes are kept as namespace and unprefixed attributes have no namespace.
+func resolveName(name string, namespaces map[string]string, element bool) xml.Name {
+	i := strings.IndexByte(name, ':')
+	if i <= 0 || i == len(name)-1 {
+		if element {
+			return xml.Name{Space: namespaces[""], Local: name}
+		}
+		return xml.Name{Local: name}
+	}
+	prefix, local := name[:i], name[i+1:]
+	if prefix == "xml" {
+		return xml.Name{Space: nsXML, Local: local}
+	}
+	if uri, ok := namespaces[prefix]; ok {
+		return xml.Name{Space: uri, Local: local}
+	}
+	return xml.Name{Space: prefix, Local: local}
+}
+
+// foreign reports whether the current element is an element of a foreign namespace like SVG.
+func (b *htmlBuilder) foreign() bool {
+	if len(b.open) == 0 {
+		return false
+	}
+	space := b.open[len(b.open)-1].name.Space
+	return space != "" && space != nsXHTML
+}
+
+// implyEndTags closes the open elements, whose end is implied by the start tag of an element.
+func (b *htmlBuilder) implyEndTags(tag string) {
+	if closesParagraph[tag] {
+		b.closeInScope([]string{"p"}, nil)
+	}
+	switch tag {
+	case "li":
+		b.closeInScope([]string{"li"}, []string{"ol", "ul"})
+	case "dt", "dd":
+		b.closeInScope([]string{"dt", "dd"}, []string{"dl"})
+	case "option", "optgroup":
+		b.closeInScope([]string{"option"}, []string{"select", "optgroup"})
+	case "td", "th":
+		b.closeInScope([]string{"td", "th"}, []string{"tr"})
+	case "tr":
+		b.closeInScope([]string{"td", "th"}, []string{"tr"})
+		b.closeInScope([]string{"tr"}, []string{"thead", "tbody", "tfoot"})
+	case "thead", "tbody", "tfoot":
+		b.closeInScope([]string{"td", "th"}, []string{"tr"})
+		b.closeInScope([]string{"tr"}, []string{"thead", "tbody", "tfoot"})
+		b.closeInScope([]string{"thead", "tbody", "tfoot"}, nil)
+	}
+}
+
+// closeInScope closes the innermost open element with one of the tags,
+// unless a scope boundary or one of the stop elements comes first.
+func (b *htmlBuilder) closeInScope(tags, stop []string) {
+	for i := len(b.open) - 1; i >= 0; i-- {
+		e := b.open[i]
+		if e.name.Space != "" && e.name.Space != nsXHTML {
+			return
+		}
+		for _, tag := range tags {
+			if e.tag == tag {
+				b.closeTo(i)
+				return
+			}
+		}
+		if scopeBoundaries[e.tag] {
+			return
+		}
+		for _, tag := range stop {
+			if e.tag == tag {
+				return
+			}
+		}
+	}
+}
+
+// end closes the innermost open element with the name of the end tag and all elements within it.
+// Stray end tags are ignored.
+func (b *htmlBuilder) end(t htmlTag) {
+	for i := len(b.open) - 1; i >= 0; i-- {
+		if strings.EqualFold(b.open[i].tag, t.name) {
+			b.closeTo(i)
+			return
+		}
+	}
+}
+
+// closeTo closes the open element at index i and all elements within it.
+func (b *htmlBuilder) closeTo(i int) {
+	for j := len(b.open) - 1; j >= i; j-- {
+		b.tokens = append(b.tokens, xml.EndElement{Name: b.open[j].name})
+	}
+	b.open = b.open[:i]
+}
+
+func (b *htmlBuilder) closeAll() {
+	b.closeTo(0)
+}
+
+// text appends character data and merges it with preceding character data.
+func (b *htmlBuilder) text(t xml.CharData) {
+	if n := len(b.tokens); n > 0 {
+		if last, ok := b.tokens[n-1].(xml.CharData); ok {
+			b.tokens[n-1] = append(append(xml.CharData{}, last...), t...)
+			return
+		}
+	}
+	b.tokens = append(b.tokens, t)
+}
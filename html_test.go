@@ -0,0 +1,68 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func Test_htmlTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraphs", `<p>a<p>b`, `<p>a</p><p>b</p>`},
+		{"block closes paragraph", `<p>a<div>b</div>`, `<p>a</p><div>b</div>`},
+		{"paragraph within button", `<button><p>a<p>b</button>`, `<button><p>a</p><p>b</p></button>`},
+		{"nested lists", `<ul><li>a<ul><li>b</ul><li>c</ul>`, `<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul>`},
+		{"definitions", `<dl><dt>a<dd>b<dt>c</dl>`, `<dl><dt>a</dt><dd>b</dd><dt>c</dt></dl>`},
+		{"table", `<table><tr><td>a<td>b<tr><th>c</table>`, `<table><tr><td>a</td><td>b</td></tr><tr><th>c</th></tr></table>`},
+		{"table sections", `<table><thead><tr><th>a<tbody><tr><td>b</table>`, `<table><thead><tr><th>a</th></tr></thead><tbody><tr><td>b</td></tr></tbody></table>`},
+		{"options", `<select><option>a<option>b</select>`, `<select><option>a</option><option>b</option></select>`},
+		{"misnested", `<b><i>a</b>b</i>`, `<b><i>a</i></b>b`},
+		{"uppercase end tag", `<EM>a</em>`, `<em>a</em>`},
+		{"bare less-than", `1 <2 < 3 <> <=`, `1 &lt;2 &lt; 3 &lt;&gt; &lt;=`},
+		{"unquoted attribute", `<a href=/posts/go.html title=a.b(c)>Go</a>`, `<a href="/posts/go.html" title="a.b(c)">Go</a>`},
+		{"single quoted attribute", `<a title='say "hi"'>hi</a>`, `<a title="say &quot;hi&quot;">hi</a>`},
+		{"unterminated tag", `<a href="go.html`, `<a href="go.html"></a>`},
+		{"self-closing", `<span/>a`, `<span></span>a`},
+		{"duplicate attribute", `<a id=a id=b>x</a>`, `<a id="a">x</a>`},
+		{"character references", `&lt;&#60;&#x3C;&amp&copy;&unknown; &#0;`, "&lt;&lt;&lt;&amp;amp©&amp;unknown; �"},
+		{"attribute references", `<a title="&lt;&amp;&quot;">x</a>`, `<a title="&lt;&amp;&quot;">x</a>`},
+		{"script", `<script>if (a < b && c) {}</p></script>x`, `<script>if (a &lt; b &amp;&amp; c) {}&lt;/p&gt;</script>x`},
+		{"title references", `<title>a &amp; <b></TITLE>`, `<title>a &amp; &lt;b&gt;</title>`},
+		{"comment", `a<!-- <p> -->b`, `a<!-- <p> -->b`},
+		{"cdata", `<![CDATA[<p>]]>`, `&lt;p&gt;`},
+		{"doctype and processing instruction", `<!DOCTYPE html><?php echo 1 ?>a`, `a`},
+		{"bogus end tag", `a</ >b</3>`, `ab`},
+		{"svg", `<svg><circle r=1 /></svg>`, `<svg xmlns="http://www.w3.org/2000/svg"><circle r="1"></circle></svg>`},
+		{"xml lang", `<p xml:lang=en>a</p>`, `<p xml:lang="en">a</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeXHTML(htmlTokens(tt.html)); got != tt.want {
+				t.Errorf("htmlTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_htmlTokensNamespaces(t *testing.T) {
+	got := htmlTokens(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="#a" x:y="1"/></svg>`)
+	want := []xml.Token{
+		xml.StartElement{Name: xml.Name{Space: nsSVG, Local: "svg"}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: nsSVG},
+			{Name: xml.Name{Space: "xmlns", Local: "xlink"}, Value: "http://www.w3.org/1999/xlink"},
+		}},
+		xml.StartElement{Name: xml.Name{Space: nsSVG, Local: "use"}, Attr: []xml.Attr{
+			{Name: xml.Name{Space: "http://www.w3.org/1999/xlink", Local: "href"}, Value: "#a"},
+			{Name: xml.Name{Space: "x", Local: "y"}, Value: "1"},
+		}},
+		xml.EndElement{Name: xml.Name{Space: nsSVG, Local: "use"}},
+		xml.EndElement{Name: xml.Name{Space: nsSVG, Local: "svg"}},
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("htmlTokens() = %#v, want %#v", got, want)
+	}
+}
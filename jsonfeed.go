@@ -0,0 +1,389 @@
+package atomfeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonFeedVersion is the version URL of the JSON Feed format written by EncodeJSON.
+//  https://www.jsonfeed.org/version/1.1/
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	NextURL     string       `json:"next_url,omitempty"`
+	Icon        string       `json:"icon,omitempty"`
+	Favicon     string       `json:"favicon,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Author      *jsonAuthor  `json:"author,omitempty"` // JSON Feed 1.0, only decoded
+	Language    string       `json:"language,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name   string `json:"name,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+type jsonItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	ExternalURL   string           `json:"external_url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	BannerImage   string           `json:"banner_image,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor     `json:"authors,omitempty"`
+	Author        *jsonAuthor      `json:"author,omitempty"` // JSON Feed 1.0, only decoded
+	Tags          []string         `json:"tags,omitempty"`
+	Language      string           `json:"language,omitempty"`
+	Attachments   []jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	Title    string `json:"title,omitempty"`
+	Size     int64  `json:"size_in_bytes,omitempty"`
+}
+
+// EncodeJSON writes the JSON Feed 1.1 encoding of Feed to the stream.
+//
+// JSON Feed is less expressive than Atom: see JSONLosses for the information, which is not part of the output.
+// Xhtml content is encoded as html and summaries are reduced to plain text.
+//  https://www.jsonfeed.org/version/1.1/
+func (f *Feed) EncodeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f.json())
+}
+
+func (f *Feed) json() *jsonFeed {
+	feed := &jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       plainText(f.Title),
+		HomePageURL: linkHref(f.Links, "alternate"),
+		FeedURL:     linkHref(f.Links, "self"),
+		Description: plainText(f.Subtitle),
+		NextURL:     linkHref(f.Links, "next"),
+		Authors:     jsonAuthors(f.Author),
+		Items:       []jsonItem{},
+	}
+	if f.Logo != nil {
+		feed.Icon = f.Logo.Value
+	}
+	if f.Icon != nil {
+		feed.Favicon = f.Icon.Value
+	}
+	if f.CommonAttributes != nil {
+		feed.Language = f.Lang
+	}
+	for _, e := range f.Entries {
+		feed.Items = append(feed.Items, e.json())
+	}
+	return feed
+}
+
+func (e *Entry) json() jsonItem {
+	item := jsonItem{
+		ID:            e.ID.Value,
+		URL:           linkHref(e.Links, "alternate"),
+		ExternalURL:   linkHref(e.Links, "related"),
+		Title:         plainText(e.Title),
+		Summary:       contentText(e.Summary),
+		DatePublished: jsonDate(e.Published),
+		DateModified:  jsonDate(e.Updated),
+		Authors:       jsonAuthors(e.Author),
+	}
+	if e.CommonAttributes != nil {
+		item.Language = e.Lang
+	}
+	for _, c := range e.Categories {
+		item.Tags = append(item.Tags, c.Term)
+	}
+	for _, l := range e.Links {
+		if l.Rel == "enclosure" {
+			size, _ := strconv.ParseInt(l.Length, 10, 64)
+			item.Attachments = append(item.Attachments, jsonAttachment{URL: l.Href, MimeType: l.Type, Title: l.Title, Size: size})
+		}
+	}
+	if c := e.Content; c != nil && c.Source == "" {
+		switch {
+		case c.Type == "html":
+			item.ContentHTML = c.Value
+		case c.Type == "xhtml":
+			item.ContentHTML = UnwrapXHTML(c.ValueXML)
+		case isTextContentType(c.Type):
+			item.ContentText = c.Value
+		}
+	}
+	if item.ContentHTML == "" && item.ContentText == "" {
+		item.ContentText = item.Summary // content is mandatory
+	}
+	return item
+}
+
+func jsonAuthors(p *Person) []jsonAuthor {
+	if p == nil {
+		return nil
+	}
+	return []jsonAuthor{{Name: p.Name, URL: p.URI}}
+}
+
+func jsonDate(d *Date) string {
+	if d.Time().IsZero() {
+		return ""
+	}
+	return d.Time().Format(time.RFC3339Nano)
+}
+
+// DecodeJSON reads a JSON Feed (version 1.0 or 1.1) from the stream and converts it into an atom feed.
+//
+// Information of the JSON Feed, which has no exact Atom equivalent, is reported as conversion losses.
+// The feed_url (or home_page_url) is used as feed ID and the feed is updated with its most recent item.
+//  https://www.jsonfeed.org/version/1.1/
+func DecodeJSON(r io.Reader) (*Feed, []string, error) {
+	doc := &jsonFeed{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, nil, err
+	}
+	if strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") == false {
+		return nil, nil, fmt.Errorf("jsonfeed: unknown version %q", doc.Version)
+	}
+	losses := []string{}
+	lose := func(format string, args ...interface{}) {
+		losses = append(losses, fmt.Sprintf(format, args...))
+	}
+	f := &Feed{
+		Namespace: nsAtom,
+		Title:     &TextConstruct{Value: doc.Title},
+	}
+	switch {
+	case doc.FeedURL != "":
+		f.ID = NewID(doc.FeedURL)
+	case doc.HomePageURL != "":
+		f.ID = NewID(doc.HomePageURL)
+	default:
+		lose("feed: no feed_url or home_page_url to derive the feed id from")
+	}
+	if f.ID.Value != "" {
+		lose("feed: JSON Feed has no feed id, %q is used instead", f.ID.Value)
+	}
+	if doc.HomePageURL != "" {
+		f.Links = append(f.Links, Link{Rel: "alternate", Type: "text/html", Href: doc.HomePageURL})
+	}
+	if doc.FeedURL != "" {
+		f.Links = append(f.Links, Link{Rel: "self", Type: "application/feed+json", Href: doc.FeedURL})
+	}
+	if doc.NextURL != "" {
+		f.Links = append(f.Links, Link{Rel: "next", Type: "application/feed+json", Href: doc.NextURL})
+	}
+	if doc.Description != "" {
+		f.Subtitle = &TextConstruct{Value: doc.Description}
+	}
+	if doc.Icon != "" {
+		f.Logo = &Logo{Value: doc.Icon}
+	}
+	if doc.Favicon != "" {
+		f.Icon = &Icon{Value: doc.Favicon}
+	}
+	if doc.Language != "" {
+		f.CommonAttributes = &CommonAttributes{Lang: doc.Language}
+	}
+	f.Author, f.Contributor = atomPersons(doc.Authors, doc.Author, func(format string, args ...interface{}) {
+		lose("feed: "+format, args...)
+	})
+	for _, item := range doc.Items {
+		lose := func(format string, args ...interface{}) {
+			lose("item "+item.ID+": "+format, args...)
+		}
+		e := Entry{
+			ID:    NewID(item.ID),
+			Title: &TextConstruct{Value: item.Title},
+		}
+		if item.Title == "" {
+			lose("no title")
+		}
+		if item.URL != "" {
+			e.Links = append(e.Links, Link{Rel: "alternate", Type: "text/html", Href: item.URL})
+		}
+		if item.ExternalURL != "" {
+			e.Links = append(e.Links, Link{Rel: "related", Href: item.ExternalURL})
+		}
+		for _, a := range item.Attachments {
+			l := Link{Rel: "enclosure", Href: a.URL, Type: a.MimeType, Title: a.Title}
+			if a.Size > 0 {
+				l.Length = strconv.FormatInt(a.Size, 10)
+			}
+			e.Links = append(e.Links, l)
+		}
+		switch {
+		case item.ContentHTML != "":
+			e.Content = &Content{Type: "html", Value: item.ContentHTML}
+			if item.ContentText != "" {
+				lose("content_text, only content_html is kept")
+			}
+		case item.ContentText != "":
+			e.Content = &Content{Type: "text", Value: item.ContentText}
+		}
+		if item.Summary != "" {
+			e.Summary = &Content{Type: "text", Value: item.Summary}
+		}
+		if item.Image != "" {
+			lose("image %q", item.Image)
+		}
+		if item.BannerImage != "" {
+			lose("banner_image %q", item.BannerImage)
+		}
+		var err error
+		if item.DatePublished != "" {
+			if e.Published, err = ParseDate(item.DatePublished); err != nil {
+				lose("date_published: %v", err)
+			}
+		}
+		if item.DateModified != "" {
+			if e.Updated, err = ParseDate(item.DateModified); err != nil {
+				lose("date_modified: %v", err)
+			}
+		}
+		if e.Updated == nil && e.Published != nil {
+			e.Updated = NewDate(e.Published.Time())
+		}
+		if e.Updated == nil {
+			lose("no date, entry has no updated date")
+		}
+		e.Author, e.Contributor = atomPersons(item.Authors, item.Author, lose)
+		for _, tag := range item.Tags {
+			e.Categories = append(e.Categories, Category{Term: tag})
+		}
+		if item.Language != "" {
+			e.CommonAttributes = &CommonAttributes{Lang: item.Language}
+		}
+		if e.Updated.Time().After(f.Updated.Time()) {
+			f.Updated = NewDate(e.Updated.Time())
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	return f, losses, nil
+}
+
+// atomPersons converts the authors of a JSON Feed into an author and contributors.
+func atomPersons(authors []jsonAuthor, author *jsonAuthor, lose func(format string, args ...interface{})) (*Person, []Person) {
+	if len(authors) == 0 && author != nil {
+		authors = []jsonAuthor{*author}
+	}
+	persons := []Person{}
+	for _, a := range authors {
+		if a.Avatar != "" {
+			lose("avatar %q of author %q", a.Avatar, a.Name)
+		}
+		persons = append(persons, Person{Name: a.Name, URI: a.URL})
+	}
+	if len(persons) == 0 {
+		return nil, nil
+	}
+	if len(persons) > 1 {
+		lose("%d additional authors are converted to contributors", len(persons)-1)
+		return &persons[0], persons[1:]
+	}
+	return &persons[0], nil
+}
+
+// JSONLosses lists information of the feed, which is lost when encoding it as JSON Feed (see EncodeJSON).
+func (f *Feed) JSONLosses() []string {
+	losses := []string{}
+	lose := func(format string, args ...interface{}) {
+		losses = append(losses, fmt.Sprintf(format, args...))
+	}
+	if f.ID.Value != "" {
+		lose("feed: JSON Feed has no feed id, %q is dropped", f.ID.Value)
+	}
+	if f.Copyright != nil {
+		lose("feed: rights")
+	}
+	if len(f.Categories) > 0 {
+		lose("feed: %d categories", len(f.Categories))
+	}
+	if f.Generator != nil {
+		lose("feed: generator")
+	}
+	if f.Updated != nil {
+		lose("feed: updated date %v", f.Updated)
+	}
+	if f.Author != nil && f.Author.Email != "" {
+		lose("feed: author email %q", f.Author.Email)
+	}
+	if len(f.Contributor) > 0 {
+		lose("feed: %d contributors", len(f.Contributor))
+	}
+	if f.Geo != nil || f.DublinCore != nil || f.OpenSearch != nil {
+		lose("feed: extension elements")
+	}
+	for _, l := range f.Links {
+		switch l.Rel {
+		case "", "alternate", "self", "next":
+		default:
+			lose("feed: %v link %q", l.Rel, l.Href)
+		}
+	}
+	for _, e := range f.Entries {
+		lose := func(format string, args ...interface{}) {
+			lose("entry "+e.ID.Value+": "+format, args...)
+		}
+		if e.Author != nil && e.Author.Email != "" {
+			lose("author email %q", e.Author.Email)
+		}
+		if len(e.Contributor) > 0 {
+			lose("%d contributors", len(e.Contributor))
+		}
+		if e.Copyright != nil {
+			lose("rights")
+		}
+		if e.Source != nil {
+			lose("source")
+		}
+		if e.Media != nil || e.Geo != nil || e.DublinCore != nil {
+			lose("extension elements")
+		}
+		for _, c := range e.Categories {
+			if c.Scheme != "" || c.Label != "" {
+				lose("scheme and label of category %q", c.Term)
+			}
+		}
+		for _, l := range e.Links {
+			switch l.Rel {
+			case "", "alternate", "related", "enclosure":
+			default:
+				lose("%v link %q", l.Rel, l.Href)
+			}
+		}
+		if e.Summary != nil && e.Summary.Type != "" && e.Summary.Type != "text" {
+			lose("markup of summary, summaries are plain text")
+		}
+		if c := e.Content; c != nil {
+			switch {
+			case c.Type == "xhtml":
+				lose("xhtml content is downgraded to html")
+			case c.Source != "":
+				lose("out-of-line content %q", c.Source)
+			case isTextContentType(c.Type) == false:
+				lose("content of type %q", c.Type)
+			}
+		}
+	}
+	return losses
+}
@@ -0,0 +1,102 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedEncodeJSON(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	f := NewFeed(NewID("tag:example.com,2005:blog"), NewPerson("Go Pher", "", "https://blog.golang.org/gopher"), "Blog", "Latest news", "https://example.com/", "https://example.com/feed.json", now, []Entry{
+		NewEntry(NewID("tag:example.com,2005:blog.post-1"), "Post 1", "https://example.com/1", nil, now, now, []string{"go"}, []byte("<em>summary</em>"), []byte("<p>content</p>")),
+	})
+	f.Entries[0].Title = &TextConstruct{Type: "html", Value: "Go &amp; <em>Gophers</em>"}
+	out := &bytes.Buffer{}
+	if err := f.EncodeJSON(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"version": "https://jsonfeed.org/version/1.1"`,
+		`"home_page_url": "https://example.com/"`,
+		`"feed_url": "https://example.com/feed.json"`,
+		`"authors": [`,
+		`"url": "https://blog.golang.org/gopher"`,
+		`"content_html": "<p>content</p>"`,
+		`"summary": "summary"`,
+		`"date_modified": "2017-12-21T08:30:15Z"`,
+		`"tags": [`,
+		`"title": "Go & Gophers"`,
+	} {
+		if strings.Contains(out.String(), want) == false {
+			t.Errorf("EncodeJSON() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+
+	decoded, _, err := DecodeJSON(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := decoded.Entries[0]
+	if decoded.ID.Value != "https://example.com/feed.json" || e.ID.Value != "tag:example.com,2005:blog.post-1" || e.Content.Value != "<p>content</p>" || e.Updated.Time() != now {
+		t.Errorf("DecodeJSON(EncodeJSON()) = %v", decoded)
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	doc := `{
+  "version": "https://jsonfeed.org/version/1",
+  "title": "Blog",
+  "home_page_url": "https://example.com/",
+  "author": {"name": "Go Pher", "avatar": "https://example.com/gopher.png"},
+  "items": [
+    {"id": "2", "title": "Two", "content_text": "text", "date_published": "2017-12-22T08:30:15Z", "attachments": [{"url": "https://example.com/2.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1024}]},
+    {"id": "1", "content_html": "<p>html</p>", "content_text": "text", "image": "https://example.com/1.png", "date_published": "2017-12-21T08:30:15Z", "date_modified": "2017-12-23T08:30:15Z"}
+  ]
+}`
+	f, losses, err := DecodeJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Author.Name != "Go Pher" || f.Updated.Time() != time.Date(2017, time.December, 23, 8, 30, 15, 0, time.UTC) {
+		t.Errorf("DecodeJSON() author = %v, updated = %v", f.Author, f.Updated)
+	}
+	if want := (Link{Rel: "enclosure", Href: "https://example.com/2.mp3", Type: "audio/mpeg", Length: "1024"}); !reflect.DeepEqual(f.Entries[0].Links[0], want) {
+		t.Errorf("DecodeJSON() attachment = %v, want %v", f.Entries[0].Links[0], want)
+	}
+	if c := f.Entries[1].Content; c.Type != "html" || f.Entries[1].Published.Time().Day() != 21 {
+		t.Errorf("DecodeJSON() content = %v, published = %v", c, f.Entries[1].Published)
+	}
+	wantLosses := []string{
+		`feed: JSON Feed has no feed id, "https://example.com/" is used instead`,
+		`feed: avatar "https://example.com/gopher.png" of author "Go Pher"`,
+		`item 1: no title`,
+		`item 1: content_text, only content_html is kept`,
+		`item 1: image "https://example.com/1.png"`,
+	}
+	if !reflect.DeepEqual(losses, wantLosses) {
+		t.Errorf("DecodeJSON() losses = %q, want %q", losses, wantLosses)
+	}
+	if _, _, err := DecodeJSON(strings.NewReader(`{"version": "1"}`)); err == nil {
+		t.Error("DecodeJSON() should fail on unknown version, did not")
+	}
+}
+
+func TestFeedJSONLosses(t *testing.T) {
+	f := Feed{
+		Copyright: NewCopyright("© Go Pher"),
+		Entries: []Entry{
+			{ID: NewID("1"), Author: &Person{Name: "Go Pher", Email: "gopher@golang.org"}, Categories: []Category{{Term: "go", Scheme: "https://example.com/tags"}}},
+		},
+	}
+	want := []string{
+		`feed: rights`,
+		`entry 1: author email "gopher@golang.org"`,
+		`entry 1: scheme and label of category "go"`,
+	}
+	if got := f.JSONLosses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONLosses() = %q, want %q", got, want)
+	}
+}
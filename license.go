@@ -0,0 +1,70 @@
+package atomfeed
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// LicenseUnspecified is the IRI of the special license link, which signals that an entry
+// does not inherit the license of its feed, but has no license of its own either.
+//  https://tools.ietf.org/html/rfc4946#section-2.3
+const LicenseUnspecified = "http://purl.org/atompub/license#unspecified"
+
+// Licenses returns the effective license links of the entry.
+//
+// An entry without license links inherits the licenses of its atom:source element
+// or – if there is none – of the given feed. Feed may be nil.
+// No licenses are returned, if the effective license is LicenseUnspecified.
+//  https://tools.ietf.org/html/rfc4946#section-2.3
+func (e *Entry) Licenses(feed *Feed) []Link {
+	candidates := [][]Link{e.Links}
+	if e.Source != nil {
+		candidates = append(candidates, e.Source.Links)
+	} else if feed != nil {
+		candidates = append(candidates, feed.Links)
+	}
+	for _, links := range candidates {
+		licenses := licenseLinks(links)
+		if len(licenses) == 0 {
+			continue
+		}
+		for _, l := range licenses {
+			if l.Href == LicenseUnspecified {
+				return nil
+			}
+		}
+		return licenses
+	}
+	return nil
+}
+
+func licenseLinks(links []Link) []Link {
+	licenses := []Link{}
+	for _, l := range links {
+		if l.Rel == "license" {
+			licenses = append(licenses, l)
+		}
+	}
+	return licenses
+}
+
+// checkLicenses verifies all license links within links.
+// The IRI of a license must be absolute and must not occur more than once per media type.
+func checkLicenses(links []Link) error {
+	seen := map[Link]bool{}
+	for _, l := range licenseLinks(links) {
+		u, err := url.Parse(l.Href)
+		if err != nil {
+			return fmt.Errorf("license: %q is not a valid IRI: %v", l.Href, err)
+		}
+		if u.IsAbs() == false {
+			return fmt.Errorf("license: %q is not an absolute IRI", l.Href)
+		}
+		key := Link{Href: l.Href, Type: l.Type}
+		if seen[key] {
+			return fmt.Errorf("license: duplicate license %q", l.Href)
+		}
+		seen[key] = true
+	}
+	return nil
+}
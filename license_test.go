@@ -0,0 +1,57 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntryLicenses(t *testing.T) {
+	ccBy := NewLicense("https://creativecommons.org/licenses/by/4.0/")
+	ccBySA := NewLicense("https://creativecommons.org/licenses/by-sa/4.0/")
+	alternate := Link{Rel: "alternate", Href: "https://example.com/post/1"}
+	feed := &Feed{Links: []Link{alternate, ccBy}}
+
+	tests := []struct {
+		name  string
+		entry Entry
+		feed  *Feed
+		want  []Link
+	}{
+		{"inherit from feed", Entry{Links: []Link{alternate}}, feed, []Link{ccBy}},
+		{"override feed", Entry{Links: []Link{alternate, ccBySA}}, feed, []Link{ccBySA}},
+		{"inherit from source", Entry{Source: &Source{Links: []Link{ccBySA}}}, feed, []Link{ccBySA}},
+		{"source without license", Entry{Source: &Source{}}, feed, nil},
+		{"unspecified", Entry{Links: []Link{NewLicense(LicenseUnspecified)}}, feed, nil},
+		{"no feed", Entry{Links: []Link{alternate}}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Licenses(tt.feed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Licenses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkLicenses(t *testing.T) {
+	ccBy := NewLicense("https://creativecommons.org/licenses/by/4.0/")
+	tests := []struct {
+		name    string
+		links   []Link
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []Link{ccBy, {Rel: "alternate", Href: "/post/1"}}, false},
+		{"relative", []Link{NewLicense("/license")}, true},
+		{"invalid", []Link{NewLicense(":license")}, true},
+		{"duplicate", []Link{ccBy, ccBy}, true},
+		{"same license different type", []Link{ccBy, {Rel: "license", Href: ccBy.Href, Type: "application/rdf+xml"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkLicenses(tt.links); (err != nil) != tt.wantErr {
+				t.Errorf("checkLicenses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
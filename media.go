@@ -0,0 +1,194 @@
+package atomfeed
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Media contains the Media RSS elements of an entry, which describe
+// images, audio and video files beyond what atom:link offers.
+//  http://www.rssboard.org/media-rss
+type Media struct {
+	// Groups bundle several media:content elements, which are
+	// different representations of the same object (e.g. sizes of a photo).
+	Groups []MediaGroup `xml:"media:group"`
+	// Contents lists the media objects of the entry.
+	Contents []MediaContent `xml:"media:content"`
+	MediaMetadata
+}
+
+// MediaGroup is a media:group element.
+// It allows grouping of media:content elements that are effectively the same content,
+// yet different representations.
+//  http://www.rssboard.org/media-rss#media-group
+type MediaGroup struct {
+	Contents []MediaContent `xml:"media:content"`
+	MediaMetadata
+}
+
+// MediaContent is a media:content element, which contains information about a single media object.
+//  http://www.rssboard.org/media-rss#media-content
+type MediaContent struct {
+	// URL specifies the direct URL to the media object.
+	URL string `xml:"url,attr,omitempty"`
+	// FileSize is the number of bytes of the media object.
+	FileSize int64 `xml:"fileSize,attr,omitempty"`
+	// Type is the standard MIME type of the object.
+	Type string `xml:"type,attr,omitempty"`
+	// Medium is the type of object and is one of "image", "audio", "video", "document" or "executable".
+	Medium string `xml:"medium,attr,omitempty"`
+	// IsDefault determines if this is the default object that should be used for the media:group.
+	IsDefault bool `xml:"isDefault,attr,omitempty"`
+	// Expression determines if the object is a "sample", "full" or "nonstop" version.
+	Expression string `xml:"expression,attr,omitempty"`
+	// Bitrate is the kilobits per second rate of media.
+	Bitrate int `xml:"bitrate,attr,omitempty"`
+	// Duration is the number of seconds the media object plays.
+	Duration int `xml:"duration,attr,omitempty"`
+	// Height is the height of the media object in pixels.
+	Height int `xml:"height,attr,omitempty"`
+	// Width is the width of the media object in pixels.
+	Width int `xml:"width,attr,omitempty"`
+	// Lang is the primary language encapsulated in the media object.
+	Lang string `xml:"lang,attr,omitempty"`
+	MediaMetadata
+}
+
+// MediaMetadata contains the optional elements, which may appear on
+// entry level, within a media:group or within a media:content element.
+// More specific elements override the more general ones.
+type MediaMetadata struct {
+	Title       *MediaText       `xml:"media:title"`
+	Description *MediaText       `xml:"media:description"`
+	Thumbnails  []MediaThumbnail `xml:"media:thumbnail"`
+	Credits     []MediaCredit    `xml:"media:credit"`
+}
+
+// MediaText is a media:title or media:description element.
+//  http://www.rssboard.org/media-rss#media-title
+//  http://www.rssboard.org/media-rss#media-description
+type MediaText struct {
+	// Type is either "plain" or "html".
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// MediaThumbnail is a media:thumbnail element and describes an image used as a representation of the media object.
+//  http://www.rssboard.org/media-rss#media-thumbnails
+type MediaThumbnail struct {
+	URL    string `xml:"url,attr"`
+	Height int    `xml:"height,attr,omitempty"`
+	Width  int    `xml:"width,attr,omitempty"`
+	// Time specifies the time offset in relation to the media object (NTP format).
+	Time string `xml:"time,attr,omitempty"`
+}
+
+// MediaCredit is a media:credit element and notes an entity's contribution to the creation of the media object.
+//  http://www.rssboard.org/media-rss#media-credit
+type MediaCredit struct {
+	// Role specifies the role the entity played, like "photographer" or "producer".
+	Role string `xml:"role,attr,omitempty"`
+	// Scheme is the URI that identifies the role scheme and defaults to "urn:ebu".
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+// NewMediaContent returns a media:content element for the media object at url.
+// The medium is one of "image", "audio", "video", "document" or "executable".
+func NewMediaContent(url, mimeType, medium string) MediaContent {
+	return MediaContent{URL: url, Type: mimeType, Medium: medium}
+}
+
+// NewMediaThumbnail returns a media:thumbnail element.
+func NewMediaThumbnail(url string, width, height int) MediaThumbnail {
+	return MediaThumbnail{URL: url, Width: width, Height: height}
+}
+
+func usesMedia(f *Feed) bool {
+	for _, e := range f.Entries {
+		if e.Media != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMedia(m *Media) error {
+	if m == nil {
+		return nil
+	}
+	if err := checkMediaMetadata(m.MediaMetadata); err != nil {
+		return err
+	}
+	for _, g := range m.Groups {
+		if len(g.Contents) == 0 {
+			return fmt.Errorf("media:group must contain at least one media:content element")
+		}
+		defaults := 0
+		for _, c := range g.Contents {
+			if c.IsDefault {
+				defaults++
+			}
+			if err := checkMediaContent(c); err != nil {
+				return err
+			}
+		}
+		if defaults > 1 {
+			return fmt.Errorf("media:group must not have more than one default media:content element")
+		}
+		if err := checkMediaMetadata(g.MediaMetadata); err != nil {
+			return err
+		}
+	}
+	for _, c := range m.Contents {
+		if err := checkMediaContent(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkMediaContent(c MediaContent) error {
+	if c.URL == "" {
+		return fmt.Errorf("media:content: url cannot be empty")
+	}
+	if err := checkURI(c.URL); err != nil {
+		return fmt.Errorf("media:content: %v", err)
+	}
+	switch c.Medium {
+	case "", "image", "audio", "video", "document", "executable":
+	default:
+		return fmt.Errorf("media:content: invalid medium %q", c.Medium)
+	}
+	switch c.Expression {
+	case "", "sample", "full", "nonstop":
+	default:
+		return fmt.Errorf("media:content: invalid expression %q", c.Expression)
+	}
+	if c.Type != "" && strings.Contains(c.Type, "/") == false {
+		return fmt.Errorf("media:content: invalid mime type: %v", c.Type)
+	}
+	return checkMediaMetadata(c.MediaMetadata)
+}
+
+func checkMediaMetadata(m MediaMetadata) error {
+	for _, t := range []*MediaText{m.Title, m.Description} {
+		if t == nil {
+			continue
+		}
+		switch t.Type {
+		case "", "plain", "html":
+		default:
+			return fmt.Errorf("media text: invalid type %q", t.Type)
+		}
+	}
+	for _, t := range m.Thumbnails {
+		if t.URL == "" {
+			return fmt.Errorf("media:thumbnail: url cannot be empty")
+		}
+		if err := checkURI(t.URL); err != nil {
+			return fmt.Errorf("media:thumbnail: %v", err)
+		}
+	}
+	return nil
+}
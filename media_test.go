@@ -0,0 +1,112 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newGalleryFeed() Feed {
+	now := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "gallery")
+	author := NewPerson("Go Pher", "gopher@example.com", "")
+	entry := NewEntry(NewEntryID(feedID, now), "Sunset", "https://example.com/gallery/sunset", author, now, now, nil, nil, []byte("<p>Sunset at the beach.</p>"))
+	photo := NewMediaContent("https://example.com/sunset.jpg", "image/jpeg", "image")
+	photo.Width, photo.Height = 1920, 1080
+	photo.IsDefault = true
+	entry.Media = &Media{
+		Groups: []MediaGroup{
+			{
+				Contents: []MediaContent{photo, NewMediaContent("https://example.com/sunset-small.jpg", "image/jpeg", "image")},
+			},
+		},
+		MediaMetadata: MediaMetadata{
+			Description: &MediaText{Type: "plain", Value: "Sunset at the beach"},
+			Thumbnails:  []MediaThumbnail{NewMediaThumbnail("https://example.com/sunset-thumb.jpg", 75, 50)},
+			Credits:     []MediaCredit{{Role: "photographer", Value: "Go Pher"}},
+		},
+	}
+	return NewFeed(feedID, author, "Gallery", "", "https://example.com", "https://example.com/feed.atom", now, []Entry{entry})
+}
+
+func TestMediaEncode(t *testing.T) {
+	feed := newGalleryFeed()
+	if err := feed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">`,
+		`<media:content url="https://example.com/sunset.jpg" type="image/jpeg" medium="image" isDefault="true" height="1080" width="1920"></media:content>`,
+		`<media:description type="plain">Sunset at the beach</media:description>`,
+		`<media:thumbnail url="https://example.com/sunset-thumb.jpg" height="50" width="75"></media:thumbnail>`,
+		`<media:credit role="photographer">Go Pher</media:credit>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Encode() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := feed.EncodeRSS(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">`,
+		`<media:group>`,
+		`<media:credit role="photographer">Go Pher</media:credit>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("EncodeRSS() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+}
+
+func TestMediaDecode(t *testing.T) {
+	feed := newGalleryFeed()
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	// consumed feeds may use any prefix for the Media RSS namespace
+	doc := strings.Replace(out.String(), "media:", "m:", -1)
+	doc = strings.Replace(doc, "xmlns:media=", "xmlns:m=", 1)
+	got, err := Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Entries) != 1 {
+		t.Fatalf("Decode() returned %d entries, want 1", len(got.Entries))
+	}
+	if want := feed.Entries[0].Media; !reflect.DeepEqual(got.Entries[0].Media, want) {
+		t.Errorf("Decode() media = %+v, want %+v", got.Entries[0].Media, want)
+	}
+}
+
+func Test_checkMedia(t *testing.T) {
+	tests := []struct {
+		name    string
+		media   *Media
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &Media{Contents: []MediaContent{NewMediaContent("https://example.com/a.mp4", "video/mp4", "video")}}, false},
+		{"missing url", &Media{Contents: []MediaContent{{Medium: "image"}}}, true},
+		{"invalid medium", &Media{Contents: []MediaContent{NewMediaContent("https://example.com/a.jpg", "image/jpeg", "photo")}}, true},
+		{"empty group", &Media{Groups: []MediaGroup{{}}}, true},
+		{"two defaults", &Media{Groups: []MediaGroup{{Contents: []MediaContent{{URL: "a.jpg", IsDefault: true}, {URL: "b.jpg", IsDefault: true}}}}}, true},
+		{"invalid thumbnail", &Media{MediaMetadata: MediaMetadata{Thumbnails: []MediaThumbnail{{URL: ":thumb.jpg"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMedia(tt.media); (err != nil) != tt.wantErr {
+				t.Errorf("checkMedia() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package atomfeed
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// Merge aggregates the entries of several feeds into a single feed ("planet").
+//
+// Entries are sorted by their updated date, newest first. Entries sharing the same ID
+// are deduplicated and only the most recently updated entry is kept.
+// Each entry receives an atom:source element with the metadata of its originating feed,
+// unless it already carries one, and inherits author, language and base of its source feed.
+// Entries without ID aren't deduplicated. The merged entries are deep copies,
+// which share no elements with the passed feeds, so neither is modified by changing the other.
+//
+// The merged feed's updated date is set to the newest entry. All other metadata like
+// ID, title, author and links must be set by the caller.
+//  https://tools.ietf.org/html/rfc4287#section-4.2.11
+func Merge(feeds ...*Feed) *Feed {
+	merged := &Feed{Namespace: nsAtom}
+	index := map[string]int{} // entry ID → position in merged.Entries
+	for _, f := range feeds {
+		if f == nil {
+			continue
+		}
+		source := f.source()
+		for _, e := range f.Entries {
+			e := mergedEntry(e, f, source)
+			if e.ID.Value == "" {
+				merged.Entries = append(merged.Entries, e)
+				continue
+			}
+			i, ok := index[e.ID.Value]
+			if ok == false {
+				index[e.ID.Value] = len(merged.Entries)
+				merged.Entries = append(merged.Entries, e)
+				continue
+			}
+			if e.Updated.Time().After(merged.Entries[i].Updated.Time()) {
+				merged.Entries[i] = e
+			}
+		}
+	}
+	sort.SliceStable(merged.Entries, func(i, j int) bool {
+		return merged.Entries[i].Updated.Time().After(merged.Entries[j].Updated.Time())
+	})
+	if len(merged.Entries) > 0 {
+		merged.Updated = NewDate(merged.Entries[0].Updated.Time())
+	}
+	return merged
+}
+
+// source returns an atom:source element with the metadata of the feed.
+func (f *Feed) source() *Source {
+	id := f.ID
+	s := &Source{
+		Generator:   f.Generator,
+		Links:       f.Links,
+		Title:       f.Title,
+		Subtitle:    f.Subtitle,
+		Icon:        f.Icon,
+		Logo:        f.Logo,
+		Categories:  f.Categories,
+		Author:      f.Author,
+		Contributor: f.Contributor,
+		Copyright:   f.Copyright,
+	}
+	if id.Value != "" {
+		s.ID = &id
+	}
+	if f.Updated != nil {
+		s.Updated = NewDate(f.Updated.Time())
+	}
+	return s
+}
+
+// mergedEntry returns a copy of the entry, which is independent of its originating feed.
+func mergedEntry(e Entry, f *Feed, source *Source) Entry {
+	e = deepCopy(reflect.ValueOf(e)).Interface().(Entry)
+	if e.Source == nil {
+		e.Source = deepCopy(reflect.ValueOf(source)).Interface().(*Source)
+	}
+	if e.Author == nil {
+		author := e.Source.Author
+		if author == nil {
+			author = f.Author
+		}
+		e.Author = deepCopy(reflect.ValueOf(author)).Interface().(*Person)
+	}
+	if f.CommonAttributes == nil {
+		return e
+	}
+	attrs := CommonAttributes{}
+	if e.CommonAttributes != nil {
+		attrs = *e.CommonAttributes
+	}
+	if attrs.Lang == "" {
+		attrs.Lang = f.Lang
+	}
+	if f.Base != "" {
+		attrs.Base = f.Base
+		if base, err := url.Parse(f.Base); err == nil && e.CommonAttributes != nil && e.Base != "" {
+			if ref, err := base.Parse(e.Base); err == nil {
+				attrs.Base = ref.String()
+			}
+		}
+	}
+	e.CommonAttributes = &attrs
+	return e
+}
+
+// deepCopy returns a copy of v, which shares no pointers, slices or maps with v.
+// Unexported fields are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(deepCopy(v.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			m.SetMapIndex(key, deepCopy(v.MapIndex(key)))
+		}
+		return m
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(v.Type()).Elem()
+		i.Set(deepCopy(v.Elem()))
+		return i
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" { // exported
+				s.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return s
+	}
+	return v
+}
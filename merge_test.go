@@ -0,0 +1,82 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2017, time.December, d, 8, 30, 15, 0, time.UTC) }
+	gopher := NewPerson("Go Pher", "", "")
+	octocat := NewPerson("Octo Cat", "", "")
+	blog1 := &Feed{
+		ID:               NewID("tag:example.com,2005:blog"),
+		Title:            &TextConstruct{Value: "Go"},
+		Author:           gopher,
+		Links:            []Link{NewLicense("https://creativecommons.org/licenses/by/4.0/")},
+		CommonAttributes: &CommonAttributes{Lang: "en", Base: "https://example.com/blog/"},
+		Entries: []Entry{
+			{ID: NewID("tag:example.com,2005:blog.post-1"), Updated: NewDate(day(1))},
+			{ID: NewID("tag:example.com,2005:shared"), Updated: NewDate(day(2)), Author: octocat, CommonAttributes: &CommonAttributes{Base: "2017/"}},
+		},
+	}
+	blog2 := &Feed{
+		ID:     NewID("tag:example.org,2005:blog"),
+		Title:  &TextConstruct{Value: "Cats"},
+		Author: octocat,
+		Entries: []Entry{
+			{ID: NewID("tag:example.org,2005:blog.post-1"), Updated: NewDate(day(3))},
+			{ID: NewID("tag:example.com,2005:shared"), Updated: NewDate(day(4))},
+		},
+	}
+
+	got := Merge(blog1, nil, blog2)
+	wantIDs := []string{"tag:example.com,2005:shared", "tag:example.org,2005:blog.post-1", "tag:example.com,2005:blog.post-1"}
+	if len(got.Entries) != len(wantIDs) {
+		t.Fatalf("Merge() returned %d entries, want %d", len(got.Entries), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if got.Entries[i].ID.Value != id {
+			t.Errorf("Merge() entry %d = %v, want %v", i, got.Entries[i].ID.Value, id)
+		}
+	}
+	if got.Updated.Time() != day(4) {
+		t.Errorf("Merge() updated = %v, want %v", got.Updated, day(4))
+	}
+	if source := got.Entries[0].Source; source == nil || source.ID.Value != blog2.ID.Value {
+		t.Errorf("Merge() kept the older duplicate entry, want entry of %v", blog2.ID.Value)
+	}
+	post := got.Entries[2]
+	if reflect.DeepEqual(post.Author, gopher) == false {
+		t.Errorf("Merge() author = %v, want %v", post.Author, gopher)
+	}
+	if post.Lang != "en" || post.Base != "https://example.com/blog/" {
+		t.Errorf("Merge() common attributes = %+v, want lang and base of source feed", *post.CommonAttributes)
+	}
+	if licenses := post.Licenses(got); len(licenses) != 1 {
+		t.Errorf("Merge() licenses = %v, want license of source feed", licenses)
+	}
+	if blog1.Entries[0].Source != nil || blog1.Entries[0].Author != nil {
+		t.Error("Merge() modified the entries of the passed feeds")
+	}
+	post.Updated.Set(day(31))
+	post.Author.Name = "changed"
+	post.Source.Title.Value = "changed"
+	if blog1.Entries[0].Updated.Time() != day(1) || gopher.Name != "Go Pher" || blog1.Title.Value != "Go" {
+		t.Error("Merge() returned entries sharing elements with the passed feeds")
+	}
+
+	shared := Merge(blog1).Entries[0]
+	if shared.Base != "https://example.com/blog/2017/" || reflect.DeepEqual(shared.Author, octocat) == false {
+		t.Errorf("Merge() base = %v, author = %v, want resolved base and own author", shared.Base, shared.Author)
+	}
+}
+
+func TestMergeWithoutIDs(t *testing.T) {
+	updated := NewDate(time.Date(2017, time.December, 1, 8, 30, 15, 0, time.UTC))
+	feed := &Feed{Entries: []Entry{{Title: &TextConstruct{Value: "one"}, Updated: updated}, {Title: &TextConstruct{Value: "two"}, Updated: updated}}}
+	if got := Merge(feed, feed); len(got.Entries) != 4 {
+		t.Errorf("Merge() returned %d entries, want all 4 entries without ID", len(got.Entries))
+	}
+}
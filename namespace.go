@@ -0,0 +1,67 @@
+package atomfeed
+
+import "encoding/xml"
+
+// Namespaces of the Atom format and of the supported extensions.
+const (
+	nsAtom            = "http://www.w3.org/2005/Atom"
+	nsXML             = "http://www.w3.org/XML/1998/namespace"
+	nsXMLNS           = "http://www.w3.org/2000/xmlns/"
+	nsMedia           = "http://search.yahoo.com/mrss/" // http://www.rssboard.org/media-rss
+	nsGeoRSS          = "http://www.georss.org/georss"  // http://www.georss.org/
+	nsGML             = "http://www.opengis.net/gml"
+	nsDublinCore      = "http://purl.org/dc/elements/1.1/" // http://dublincore.org/documents/dces/
+	nsDublinCoreTerms = "http://purl.org/dc/terms/"
+	nsOpenSearch      = "http://a9.com/-/spec/opensearch/1.1/"     // http://www.opensearch.org/Specifications/OpenSearch/1.1
+	nsContent         = "http://purl.org/rss/1.0/modules/content/" // http://web.resource.org/rss/1.0/modules/content/
+)
+
+// namespace describes an extension namespace, which gets declared
+// on the document element as soon as a feed makes use of it.
+type namespace struct {
+	prefix string
+	uri    string
+	used   func(f *Feed) bool
+}
+
+// namespaces lists all supported extension namespaces in declaration order.
+var namespaces = []namespace{
+	{prefix: "media", uri: nsMedia, used: usesMedia},
+	{prefix: "georss", uri: nsGeoRSS, used: usesGeoRSS},
+	{prefix: "gml", uri: nsGML, used: usesGML},
+	{prefix: "dc", uri: nsDublinCore, used: usesDublinCore},
+	{prefix: "dcterms", uri: nsDublinCoreTerms, used: usesDublinCoreTerms},
+	{prefix: "opensearch", uri: nsOpenSearch, used: usesOpenSearch},
+}
+
+// prefixes maps namespace URIs to the prefixes used for elements and attributes in this package.
+var prefixes = func() map[string]string {
+	m := map[string]string{nsXML: "xml", nsContent: "content"}
+	for _, ns := range namespaces {
+		m[ns.uri] = ns.prefix
+	}
+	return m
+}()
+
+// namespaceAttrs returns the namespace declarations for all extensions used by the feed.
+func (f *Feed) namespaceAttrs() []xml.Attr {
+	attrs := []xml.Attr{}
+	for _, ns := range namespaces {
+		if ns.used(f) {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + ns.prefix}, Value: ns.uri})
+		}
+	}
+	return attrs
+}
+
+// MarshalXML encodes the atom:feed element and declares the namespaces of all extensions in use.
+func (f Feed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type feed Feed // feed has no methods and thus prevents recursion
+	start.Name = xml.Name{Local: "feed"}
+	if f.Namespace != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: f.Namespace})
+		f.Namespace = ""
+	}
+	start.Attr = append(start.Attr, f.namespaceAttrs()...)
+	return e.EncodeElement(feed(f), start)
+}
@@ -0,0 +1,250 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OpenSearch contains the OpenSearch response elements of a feed, which
+// describe a single page of search results.
+//  http://www.opensearch.org/Specifications/OpenSearch/1.1#OpenSearch_response_elements
+type OpenSearch struct {
+	// TotalResults is the number of search results available for the current search.
+	TotalResults int `xml:"opensearch:totalResults"`
+	// StartIndex is the index of the first search result in the current set of search results.
+	StartIndex int `xml:"opensearch:startIndex,omitempty"`
+	// ItemsPerPage is the number of search results returned per page.
+	ItemsPerPage int `xml:"opensearch:itemsPerPage,omitempty"`
+	// Queries define the search request, which was used to create the search results.
+	Queries []OpenSearchQuery `xml:"opensearch:Query"`
+}
+
+// OpenSearchQuery is an opensearch:Query element, which defines a search request.
+//  http://www.opensearch.org/Specifications/OpenSearch/1.1#The_.22Query.22_element
+type OpenSearchQuery struct {
+	// Role is one of "request", "example", "related", "correction", "subset" or "superset".
+	Role         string `xml:"role,attr"`
+	Title        string `xml:"title,attr,omitempty"`
+	TotalResults int    `xml:"totalResults,attr,omitempty"`
+	SearchTerms  string `xml:"searchTerms,attr,omitempty"`
+	Count        int    `xml:"count,attr,omitempty"`
+	StartIndex   int    `xml:"startIndex,attr,omitempty"`
+	StartPage    int    `xml:"startPage,attr,omitempty"`
+	Language     string `xml:"language,attr,omitempty"`
+}
+
+// OpenSearchDescription is an OpenSearch description document, which describes the
+// web interface of a search engine.
+//  http://www.opensearch.org/Specifications/OpenSearch/1.1#OpenSearch_description_document
+type OpenSearchDescription struct {
+	XMLName   xml.Name `xml:"OpenSearchDescription"`
+	Namespace string   `xml:"xmlns,attr"` // xmlns="http://a9.com/-/spec/opensearch/1.1/"
+	// ShortName contains a brief human-readable title of at most 16 characters.
+	ShortName string `xml:"ShortName"`
+	// Description contains a human-readable text description of at most 1024 characters.
+	Description string `xml:"Description"`
+	// URLs describe the interfaces by which a client can make search requests.
+	URLs    []OpenSearchURL `xml:"Url"`
+	Contact string          `xml:"Contact,omitempty"`
+	// Tags contains a space-delimited set of words used as keywords.
+	Tags           string            `xml:"Tags,omitempty"`
+	LongName       string            `xml:"LongName,omitempty"`
+	Queries        []OpenSearchQuery `xml:"Query"`
+	Language       string            `xml:"Language,omitempty"`
+	InputEncoding  string            `xml:"InputEncoding,omitempty"`
+	OutputEncoding string            `xml:"OutputEncoding,omitempty"`
+}
+
+// OpenSearchURL is an Url element of an OpenSearch description document.
+//  http://www.opensearch.org/Specifications/OpenSearch/1.1#The_.22Url.22_element
+type OpenSearchURL struct {
+	// Template is a parameterized URL like "https://example.com/search?q={searchTerms}&start={startIndex?}".
+	Template string `xml:"template,attr"`
+	// Type is the media type of the search results, like "application/atom+xml".
+	Type string `xml:"type,attr"`
+	// Rel is one of "results", "suggestions", "self" or "collection".
+	Rel string `xml:"rel,attr,omitempty"`
+	// IndexOffset is the index number of the first search result and defaults to 1.
+	IndexOffset int `xml:"indexOffset,attr,omitempty"`
+	// PageOffset is the page number of the first set of search results and defaults to 1.
+	PageOffset int `xml:"pageOffset,attr,omitempty"`
+}
+
+// NewOpenSearch returns the OpenSearch response elements for a page of search results.
+// The startIndex is the index of the first result on this page, starting at 1.
+func NewOpenSearch(searchTerms string, totalResults, startIndex, itemsPerPage int) *OpenSearch {
+	return &OpenSearch{
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Queries:      []OpenSearchQuery{{Role: "request", SearchTerms: searchTerms, StartIndex: startIndex, Count: itemsPerPage}},
+	}
+}
+
+// NewSearchLink returns an atom:link element, which references an OpenSearch description document.
+func NewSearchLink(descriptionURL, title string) Link {
+	return Link{Rel: "search", Type: "application/opensearchdescription+xml", Href: descriptionURL, Title: title}
+}
+
+// NewOpenSearchDescription returns an OpenSearch description document
+// for a search engine, which returns its results as Atom feed.
+func NewOpenSearchDescription(shortName, description, template string) *OpenSearchDescription {
+	return &OpenSearchDescription{
+		Namespace:   nsOpenSearch,
+		ShortName:   shortName,
+		Description: description,
+		URLs:        []OpenSearchURL{{Template: template, Type: "application/atom+xml", Rel: "results"}},
+	}
+}
+
+// Encode writes the XML encoding of the OpenSearch description document to the stream.
+func (d *OpenSearchDescription) Encode(w io.Writer) error {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return enc.Encode(d)
+}
+
+// Verify checks an OpenSearch description document for most common errors.
+func (d *OpenSearchDescription) Verify() *VerificationError {
+	errors := []error{}
+	if d.ShortName == "" {
+		errors = append(errors, fmt.Errorf("opensearch: missing short name"))
+	} else if len([]rune(d.ShortName)) > 16 {
+		errors = append(errors, fmt.Errorf("opensearch: short name %q exceeds 16 characters", d.ShortName))
+	}
+	if d.Description == "" {
+		errors = append(errors, fmt.Errorf("opensearch: missing description"))
+	} else if len([]rune(d.Description)) > 1024 {
+		errors = append(errors, fmt.Errorf("opensearch: description exceeds 1024 characters"))
+	}
+	if len(d.URLs) == 0 {
+		errors = append(errors, fmt.Errorf("opensearch: missing url template"))
+	}
+	for _, u := range d.URLs {
+		if u.Template == "" {
+			errors = append(errors, fmt.Errorf("opensearch: url: template cannot be empty"))
+		}
+		if strings.Contains(u.Type, "/") == false {
+			errors = append(errors, fmt.Errorf("opensearch: url: invalid mime type: %v", u.Type))
+		}
+	}
+	for _, q := range d.Queries {
+		if err := checkOpenSearchQuery(q); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return &VerificationError{Errors: errors}
+	}
+	return nil
+}
+
+var templateParameter = regexp.MustCompile(`\{([^}?]+)(\??)\}`)
+
+// Expand replaces the parameters of the URL template with the given values.
+// Values are URL encoded. Missing optional parameters are replaced with an empty string,
+// while missing mandatory parameters result in an error.
+func (u OpenSearchURL) Expand(params map[string]string) (string, error) {
+	var err error
+	expanded := templateParameter.ReplaceAllStringFunc(u.Template, func(m string) string {
+		match := templateParameter.FindStringSubmatch(m)
+		if value, ok := params[match[1]]; ok {
+			return url.QueryEscape(value)
+		}
+		if match[2] == "" && err == nil {
+			err = fmt.Errorf("opensearch: missing value for mandatory template parameter %q", match[1])
+		}
+		return ""
+	})
+	return expanded, err
+}
+
+// PagingLinks returns the links to the "first", "previous", "next" and "last" pages
+// of the search results (as defined by RFC 5005), built from the given URL template.
+// Links to pages which do not exist are omitted.
+//  https://tools.ietf.org/html/rfc5005#section-3
+func (o *OpenSearch) PagingLinks(template OpenSearchURL, searchTerms string) ([]Link, error) {
+	indexOffset, pageOffset := template.IndexOffset, template.PageOffset
+	if indexOffset == 0 {
+		indexOffset = 1
+	}
+	if pageOffset == 0 {
+		pageOffset = 1
+	}
+	perPage := o.ItemsPerPage
+	if perPage <= 0 {
+		return nil, fmt.Errorf("opensearch: items per page must be positive to build paging links")
+	}
+	start := o.StartIndex
+	if start < indexOffset {
+		start = indexOffset
+	}
+	last := indexOffset
+	if o.TotalResults > 0 {
+		last = indexOffset + (o.TotalResults-1)/perPage*perPage
+	}
+	pages := []struct {
+		rel   string
+		index int
+		ok    bool
+	}{
+		{"first", indexOffset, true},
+		{"previous", start - perPage, start-perPage >= indexOffset},
+		{"next", start + perPage, start+perPage <= last},
+		{"last", last, true},
+	}
+	links := []Link{}
+	for _, p := range pages {
+		if p.ok == false {
+			continue
+		}
+		href, err := template.Expand(map[string]string{
+			"searchTerms": searchTerms,
+			"count":       strconv.Itoa(perPage),
+			"startIndex":  strconv.Itoa(p.index),
+			"startPage":   strconv.Itoa(pageOffset + (p.index-indexOffset)/perPage),
+		})
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, Link{Rel: p.rel, Type: template.Type, Href: href})
+	}
+	return links, nil
+}
+
+func usesOpenSearch(f *Feed) bool {
+	return f.OpenSearch != nil
+}
+
+func checkOpenSearch(o *OpenSearch) error {
+	if o == nil {
+		return nil
+	}
+	if o.TotalResults < 0 || o.StartIndex < 0 || o.ItemsPerPage < 0 {
+		return fmt.Errorf("opensearch: result counts cannot be negative")
+	}
+	for _, q := range o.Queries {
+		if err := checkOpenSearchQuery(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkOpenSearchQuery(q OpenSearchQuery) error {
+	switch q.Role {
+	case "request", "example", "related", "correction", "subset", "superset":
+		return nil
+	case "":
+		return fmt.Errorf("opensearch: query: missing role")
+	}
+	return fmt.Errorf("opensearch: query: invalid role %q", q.Role)
+}
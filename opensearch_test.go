@@ -0,0 +1,118 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenSearchEncode(t *testing.T) {
+	now := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feed := NewFeed(NewFeedID("example.com", now, "search"), NewPerson("Go Pher", "", ""), "Search results for gopher", "", "https://example.com", "https://example.com/search?q=gopher", now, nil)
+	feed.OpenSearch = NewOpenSearch("gopher", 42, 11, 10)
+	feed.Links = append(feed.Links, NewSearchLink("https://example.com/opensearch.xml", "Example Search"))
+	if err := feed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">`,
+		`<link href="https://example.com/opensearch.xml" rel="search" type="application/opensearchdescription+xml" title="Example Search"></link>`,
+		"<opensearch:totalResults>42</opensearch:totalResults>\n  <opensearch:startIndex>11</opensearch:startIndex>\n  <opensearch:itemsPerPage>10</opensearch:itemsPerPage>",
+		`<opensearch:Query role="request" searchTerms="gopher" count="10" startIndex="11"></opensearch:Query>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Encode() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+	got, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.OpenSearch, feed.OpenSearch) {
+		t.Errorf("Decode() opensearch = %+v, want %+v", got.OpenSearch, feed.OpenSearch)
+	}
+}
+
+func TestOpenSearchPagingLinks(t *testing.T) {
+	template := OpenSearchURL{Template: "https://example.com/search?q={searchTerms}&start={startIndex}&page={startPage?}&lang={language?}", Type: "application/atom+xml"}
+	tests := []struct {
+		name   string
+		search *OpenSearch
+		want   []string
+	}{
+		{"first page", NewOpenSearch("go pher", 42, 1, 10), []string{
+			"first https://example.com/search?q=go+pher&start=1&page=1&lang=",
+			"next https://example.com/search?q=go+pher&start=11&page=2&lang=",
+			"last https://example.com/search?q=go+pher&start=41&page=5&lang=",
+		}},
+		{"middle page", NewOpenSearch("go pher", 42, 21, 10), []string{
+			"first https://example.com/search?q=go+pher&start=1&page=1&lang=",
+			"previous https://example.com/search?q=go+pher&start=11&page=2&lang=",
+			"next https://example.com/search?q=go+pher&start=31&page=4&lang=",
+			"last https://example.com/search?q=go+pher&start=41&page=5&lang=",
+		}},
+		{"last page", NewOpenSearch("go pher", 40, 31, 10), []string{
+			"first https://example.com/search?q=go+pher&start=1&page=1&lang=",
+			"previous https://example.com/search?q=go+pher&start=21&page=3&lang=",
+			"last https://example.com/search?q=go+pher&start=31&page=4&lang=",
+		}},
+		{"no results", NewOpenSearch("go pher", 0, 1, 10), []string{
+			"first https://example.com/search?q=go+pher&start=1&page=1&lang=",
+			"last https://example.com/search?q=go+pher&start=1&page=1&lang=",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := tt.search.PagingLinks(template, "go pher")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, l := range links {
+				got = append(got, l.Rel+" "+l.Href)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PagingLinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := (&OpenSearch{}).PagingLinks(template, "go"); err == nil {
+		t.Error("expected an error on missing items per page, got none")
+	}
+	if _, err := NewOpenSearch("go", 1, 1, 1).PagingLinks(OpenSearchURL{Template: "/search?q={searchTerms}&key={apiKey}"}, "go"); err == nil {
+		t.Error("expected an error on missing mandatory template parameter, got none")
+	}
+}
+
+func TestOpenSearchDescription(t *testing.T) {
+	d := NewOpenSearchDescription("Example", "Search the example.com blog.", "https://example.com/search?q={searchTerms}&start={startIndex?}")
+	if err := d.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := d.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">
+  <ShortName>Example</ShortName>
+  <Description>Search the example.com blog.</Description>
+  <Url template="https://example.com/search?q={searchTerms}&amp;start={startIndex?}" type="application/atom+xml" rel="results"></Url>
+</OpenSearchDescription>`
+	if got := out.String(); got != want {
+		t.Errorf("Encode() returned unexpected result\n\ngot:\n%v\n\nwant:\n%v", got, want)
+	}
+
+	d.ShortName = "A very long short name"
+	d.URLs[0].Type = "atom"
+	d.Queries = []OpenSearchQuery{{SearchTerms: "go"}}
+	if err := d.Verify(); err == nil || len(err.Errors) != 3 {
+		t.Errorf("Verify() = %v, want 3 errors", err)
+	}
+}
@@ -0,0 +1,49 @@
+package atomfeed
+
+// Pages splits the feed into pages of at most size entries, which are linked with each other
+// as a paged feed by "first", "previous", "next" and "last" links (as defined by RFC 5005).
+// pageURL returns the URL of a page (starting at 1), which is used as self link of the page.
+// All pages keep the metadata of the feed, while their updated date is recomputed from their entries.
+// A size of zero or less returns a single page.
+//  https://tools.ietf.org/html/rfc5005#section-3
+func (f *Feed) Pages(size int, pageURL func(page int) string) []Feed {
+	if size <= 0 || len(f.Entries) <= size {
+		size = len(f.Entries)
+	}
+	count := 1
+	if size > 0 {
+		count = (len(f.Entries) + size - 1) / size
+	}
+	pages := []Feed{}
+	for page := 1; page <= count; page++ {
+		end := page * size
+		if end > len(f.Entries) {
+			end = len(f.Entries)
+		}
+		p := f.withEntries(append([]Entry{}, f.Entries[(page-1)*size:end]...))
+		p.Links = []Link{}
+		for _, l := range f.Links {
+			switch l.Rel {
+			case "self", "first", "previous", "next", "last":
+				continue
+			}
+			p.Links = append(p.Links, l)
+		}
+		link := func(rel string, page int) {
+			p.Links = append(p.Links, Link{Rel: rel, Type: "application/atom+xml", Href: pageURL(page)})
+		}
+		link("self", page)
+		if count > 1 {
+			link("first", 1)
+			if page > 1 {
+				link("previous", page-1)
+			}
+			if page < count {
+				link("next", page+1)
+			}
+			link("last", count)
+		}
+		pages = append(pages, p)
+	}
+	return pages
+}
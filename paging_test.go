@@ -0,0 +1,49 @@
+package atomfeed
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFeedPages(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2017, time.December, d, 8, 30, 15, 0, time.UTC) }
+	feed := Feed{
+		Links:   []Link{{Rel: "alternate", Href: "https://example.com/"}, {Rel: "self", Href: "https://example.com/feed.atom"}},
+		Updated: NewDate(day(31)),
+		Entries: []Entry{{ID: NewID("5"), Updated: NewDate(day(5))}, {ID: NewID("4"), Updated: NewDate(day(4))}, {ID: NewID("3"), Updated: NewDate(day(3))}, {ID: NewID("2"), Updated: NewDate(day(2))}, {ID: NewID("1"), Updated: NewDate(day(1))}},
+	}
+	pageURL := func(page int) string { return fmt.Sprintf("https://example.com/feed-%d.atom", page) }
+	pages := feed.Pages(2, pageURL)
+	if len(pages) != 3 {
+		t.Fatalf("Pages() returned %d pages, want 3", len(pages))
+	}
+	rels := func(links []Link) []string {
+		r := []string{}
+		for _, l := range links {
+			r = append(r, l.Rel+" "+l.Href)
+		}
+		return r
+	}
+	want := []string{
+		"alternate https://example.com/",
+		"self https://example.com/feed-2.atom",
+		"first https://example.com/feed-1.atom",
+		"previous https://example.com/feed-1.atom",
+		"next https://example.com/feed-3.atom",
+		"last https://example.com/feed-3.atom",
+	}
+	if got := rels(pages[1].Links); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pages() links = %v, want %v", got, want)
+	}
+	if len(pages[2].Entries) != 1 || pages[2].Updated.Time() != day(1) || pages[0].Updated.Time() != day(5) {
+		t.Errorf("Pages() last page = %v, updated %v", pages[2].Entries, pages[2].Updated)
+	}
+	if single := feed.Pages(0, pageURL); len(single) != 1 || len(single[0].Entries) != 5 || len(single[0].Links) != 2 {
+		t.Errorf("Pages(0) = %v, want a single page without paging links", single)
+	}
+	if empty := (&Feed{}).Pages(10, pageURL); len(empty) != 1 {
+		t.Errorf("Pages() of empty feed = %v, want a single page", empty)
+	}
+}
@@ -0,0 +1,106 @@
+package atomfeed
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortByUpdated returns a copy of the feed with its entries sorted by their updated date, newest first.
+func (f *Feed) SortByUpdated() *Feed {
+	return f.sorted(func(e *Entry) time.Time { return e.Updated.Time() })
+}
+
+// SortByPublished returns a copy of the feed with its entries sorted by their published date, newest first.
+// Entries without published date are sorted by their updated date.
+func (f *Feed) SortByPublished() *Feed {
+	return f.sorted(func(e *Entry) time.Time {
+		if e.Published != nil {
+			return e.Published.Time()
+		}
+		return e.Updated.Time()
+	})
+}
+
+func (f *Feed) sorted(date func(e *Entry) time.Time) *Feed {
+	entries := append([]Entry{}, f.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return date(&entries[i]).After(date(&entries[j]))
+	})
+	feed := f.withEntries(entries)
+	return &feed
+}
+
+// Filter returns a copy of the feed, which only contains the entries for which keep returns true.
+func (f *Feed) Filter(keep func(Entry) bool) *Feed {
+	entries := []Entry{}
+	for _, e := range f.Entries {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+	feed := f.withEntries(entries)
+	return &feed
+}
+
+// Limit returns a copy of the feed, which only contains the first n entries.
+func (f *Feed) Limit(n int) *Feed {
+	if n < 0 {
+		n = 0
+	}
+	if n > len(f.Entries) {
+		n = len(f.Entries)
+	}
+	feed := f.withEntries(append([]Entry{}, f.Entries[:n]...))
+	return &feed
+}
+
+// ByCategory returns a copy of the feed, which only contains entries of the category term.
+func (f *Feed) ByCategory(term string) *Feed {
+	return f.Filter(func(e Entry) bool {
+		for _, c := range e.Categories {
+			if c.Term == term {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ByAuthor returns a copy of the feed, which only contains entries written by the named author.
+// Names are compared case-insensitively. Entries without author inherit the author of the feed.
+func (f *Feed) ByAuthor(name string) *Feed {
+	return f.Filter(func(e Entry) bool {
+		author := e.Author
+		if author == nil {
+			author = f.Author
+		}
+		return author != nil && strings.EqualFold(author.Name, name)
+	})
+}
+
+// Since returns a copy of the feed, which only contains entries updated at or after t.
+func (f *Feed) Since(t time.Time) *Feed {
+	return f.Filter(func(e Entry) bool {
+		return e.Updated != nil && e.Updated.Time().Before(t) == false
+	})
+}
+
+// withEntries returns a copy of the feed with the given entries.
+// The updated date of the feed is set to the most recently updated entry,
+// a copy without updated entries has no updated date.
+func (f *Feed) withEntries(entries []Entry) Feed {
+	feed := *f
+	feed.Entries = entries
+	var updated time.Time
+	for _, e := range entries {
+		if t := e.Updated.Time(); t.After(updated) {
+			updated = t
+		}
+	}
+	feed.Updated = nil
+	if updated.IsZero() == false {
+		feed.Updated = NewDate(updated)
+	}
+	return feed
+}
@@ -0,0 +1,63 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFeedQueries(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2017, time.December, d, 8, 30, 15, 0, time.UTC) }
+	gopher := NewPerson("Go Pher", "", "")
+	feed := Feed{
+		Title:   &TextConstruct{Value: "Blog"},
+		Author:  gopher,
+		Updated: NewDate(day(31)),
+		Entries: []Entry{
+			{ID: NewID("1"), Updated: NewDate(day(1)), Published: NewDate(day(1)), Categories: []Category{*NewCategory("go")}},
+			{ID: NewID("3"), Updated: NewDate(day(3)), Published: NewDate(day(0)), Author: NewPerson("Octo Cat", "", "")},
+			{ID: NewID("2"), Updated: NewDate(day(2)), Categories: []Category{*NewCategory("go"), *NewCategory("news")}},
+		},
+	}
+	tests := []struct {
+		name        string
+		got         *Feed
+		wantIDs     []string
+		wantUpdated time.Time
+	}{
+		{"SortByUpdated", feed.SortByUpdated(), []string{"3", "2", "1"}, day(3)},
+		{"SortByPublished", feed.SortByPublished(), []string{"2", "1", "3"}, day(3)},
+		{"Filter", feed.Filter(func(e Entry) bool { return e.ID.Value != "3" }), []string{"1", "2"}, day(2)},
+		{"Limit", feed.Limit(1), []string{"1"}, day(1)},
+		{"Limit beyond", feed.Limit(10), []string{"1", "3", "2"}, day(3)},
+		{"ByCategory", feed.ByCategory("go"), []string{"1", "2"}, day(2)},
+		{"ByAuthor", feed.ByAuthor("go pher"), []string{"1", "2"}, day(2)},
+		{"Since", feed.Since(day(2)), []string{"3", "2"}, day(3)},
+		{"empty", feed.ByCategory("cats"), []string{}, time.Time{}},
+		{"chained", feed.ByCategory("go").SortByUpdated().Limit(1), []string{"2"}, day(2)},
+		{"chained empty", feed.Since(day(4)).SortByPublished(), []string{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []string{}
+			for _, e := range tt.got.Entries {
+				ids = append(ids, e.ID.Value)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("%v() = %v, want %v", tt.name, ids, tt.wantIDs)
+			}
+			if got := tt.got.Updated.Time(); !got.Equal(tt.wantUpdated) {
+				t.Errorf("%v() updated = %v, want %v", tt.name, got, tt.wantUpdated)
+			}
+			if len(tt.wantIDs) == 0 && tt.got.Updated != nil {
+				t.Errorf("%v() updated = %v, want none without entries", tt.name, tt.got.Updated.Value)
+			}
+			if tt.got.Title != feed.Title {
+				t.Errorf("%v() must keep feed metadata", tt.name)
+			}
+		})
+	}
+	if feed.Entries[0].ID.Value != "1" || feed.Updated.Time() != day(31) {
+		t.Error("queries must not modify the original feed")
+	}
+}
@@ -0,0 +1,235 @@
+package atomfeed
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+)
+
+// Theme renders feeds and entries as HTML pages with html/template.
+// A theme defines the templates "feed", which renders the index page of a feed
+// and is executed with a *Feed, and "entry", which renders the page of a single entry
+// and is executed with an *EntryPage.
+type Theme struct {
+	t *template.Template
+}
+
+// EntryPage is the data of the "entry" template of a theme.
+type EntryPage struct {
+	Feed  *Feed
+	Entry *Entry
+}
+
+// Built-in themes.
+var (
+	// DefaultTheme renders complete pages with a simple stylesheet.
+	DefaultTheme = mustTheme("default", defaultTheme)
+	// MinimalTheme renders unstyled pages, which are easily styled by site stylesheets.
+	MinimalTheme = mustTheme("minimal", minimalTheme)
+)
+
+// NewTheme parses a theme, which must define the templates "feed" and "entry".
+// The templates may use the functions of TemplateFuncs.
+func NewTheme(name, text string) (*Theme, error) {
+	t, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"feed", "entry"} {
+		if t.Lookup(name) == nil {
+			return nil, fmt.Errorf("theme %v: missing template %q", t.Name(), name)
+		}
+	}
+	return &Theme{t: t}, nil
+}
+
+func mustTheme(name, text string) *Theme {
+	theme, err := NewTheme(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return theme
+}
+
+// RenderHTML renders the index page of the feed, which lists all entries.
+func (f *Feed) RenderHTML(w io.Writer, theme *Theme) error {
+	return theme.t.ExecuteTemplate(w, "feed", f)
+}
+
+// RenderEntryHTML renders the page of a single entry of the feed.
+func (f *Feed) RenderEntryHTML(w io.Writer, theme *Theme, e *Entry) error {
+	return theme.t.ExecuteTemplate(w, "entry", &EntryPage{Feed: f, Entry: e})
+}
+
+// TemplateFuncs returns the functions available in themes for use with custom html/template templates:
+//  formatDate layout date  formats a *Date, e.g. {{.Updated | formatDate "2 Jan 2006"}}
+//  safeContent value       renders a *Content or *TextConstruct according to its type
+//  absURL base ref         resolves ref against base, e.g. {{absURL (alternate $.Feed.Links) .Href}}
+//  plainText text          returns a *TextConstruct without markup, e.g. within <title>
+//  alternate links         returns the href of the first alternate link
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatDate":  formatDate,
+		"safeContent": safeContent,
+		"absURL":      absURL,
+		"plainText":   plainText,
+		"alternate":   func(links []Link) string { return linkHref(links, "alternate") },
+	}
+}
+
+// formatDate formats a date with a time.Format layout; missing dates result in an empty string.
+func formatDate(layout string, d *Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.Time().Format(layout)
+}
+
+// safeContent renders content and text constructs as HTML.
+// Text is escaped, html is trusted (use Sanitize on untrusted feeds) and xhtml is inlined
+// without its div wrapper. Out-of-line content is linked, other media types are omitted.
+func safeContent(value interface{}) (template.HTML, error) {
+	switch v := value.(type) {
+	case *TextConstruct:
+		if v == nil {
+			return "", nil
+		}
+		return markup(v.Type, v.Value, v.ValueXML)
+	case *Content:
+		if v == nil {
+			return "", nil
+		}
+		if v.Source != "" {
+			src := template.HTMLEscapeString(v.Source)
+			return template.HTML(`<a href="` + src + `">` + src + `</a>`), nil
+		}
+		if v.base64Encoded {
+			return "", nil
+		}
+		return markup(v.Type, v.Value, v.ValueXML)
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("safeContent: unsupported type %T", value)
+}
+
+func markup(contentType, value, valueXML string) (template.HTML, error) {
+	switch contentType {
+	case "html":
+		return template.HTML(value), nil
+	case "xhtml":
+		return template.HTML(UnwrapXHTML(valueXML)), nil
+	}
+	if valueXML != "" {
+		value = valueXML // other XML media types are displayed as source
+	}
+	return template.HTML(template.HTMLEscapeString(value)), nil
+}
+
+// plainText returns a text construct as plain text. Markup of html and xhtml is removed.
+func plainText(t *TextConstruct) string {
+	if t != nil && t.Type == "html" {
+		return htmlToText(t.Value)
+	}
+	return textValue(t)
+}
+
+// absURL resolves ref against base. Invalid URLs are returned unchanged.
+func absURL(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+const defaultTheme = `
+{{define "head"}}<!DOCTYPE html>
+<html{{with .CommonAttributes}}{{with .Lang}} lang="{{.}}"{{end}}{{end}}>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{range .Links}}{{if eq .Rel "self"}}<link rel="alternate" type="application/atom+xml" href="{{.Href}}">
+{{end}}{{end}}<style>
+body { font-family: sans-serif; max-width: 46em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+header, footer, .meta { color: #666; }
+article { border-top: 1px solid #ddd; padding: 1em 0; }
+img { max-width: 100%; }
+</style>
+{{end}}
+
+{{define "meta"}}<p class="meta"><time datetime="{{formatDate "2006-01-02T15:04:05Z07:00" .Updated}}">{{formatDate "2 January 2006" .Updated}}</time>{{with .Author}} · {{.Name}}{{end}}{{range .Categories}} · {{if .Label}}{{.Label}}{{else}}{{.Term}}{{end}}{{end}}</p>{{end}}
+
+{{define "feed"}}{{template "head" .}}<title>{{plainText .Title}}</title>
+</head>
+<body>
+<header>
+<h1>{{safeContent .Title}}</h1>
+{{with .Subtitle}}<p>{{safeContent .}}</p>{{end}}
+</header>
+<main>
+{{range .Entries}}<article>
+<h2>{{with alternate .Links}}<a href="{{absURL (alternate $.Links) .}}">{{end}}{{safeContent .Title}}{{if alternate .Links}}</a>{{end}}</h2>
+{{template "meta" .}}
+<div>{{if .Summary}}{{safeContent .Summary}}{{else}}{{safeContent .Content}}{{end}}</div>
+</article>
+{{end}}</main>
+<footer>{{with .Copyright}}<p>{{safeContent .}}</p>{{end}}<p>Updated <time datetime="{{formatDate "2006-01-02T15:04:05Z07:00" .Updated}}">{{formatDate "2 January 2006" .Updated}}</time></p></footer>
+</body>
+</html>
+{{end}}
+
+{{define "entry"}}{{template "head" .Feed}}<title>{{plainText .Entry.Title}} – {{plainText .Feed.Title}}</title>
+</head>
+<body>
+<header>
+<p><a href="{{alternate .Feed.Links}}">{{safeContent .Feed.Title}}</a></p>
+</header>
+<main>
+{{with .Entry}}<article>
+<h1>{{safeContent .Title}}</h1>
+{{template "meta" .}}
+<div>{{if .Content}}{{safeContent .Content}}{{else}}{{safeContent .Summary}}{{end}}</div>
+</article>{{end}}
+</main>
+<footer>{{with .Entry.Copyright}}<p>{{safeContent .}}</p>{{else}}{{with .Feed.Copyright}}<p>{{safeContent .}}</p>{{end}}{{end}}</footer>
+</body>
+</html>
+{{end}}
+`
+
+const minimalTheme = `
+{{define "feed"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{plainText .Title}}</title>
+</head>
+<body>
+<h1>{{safeContent .Title}}</h1>
+<ul>
+{{range .Entries}}<li>{{with alternate .Links}}<a href="{{absURL (alternate $.Links) .}}">{{end}}{{safeContent .Title}}{{if alternate .Links}}</a>{{end}} <time datetime="{{formatDate "2006-01-02T15:04:05Z07:00" .Updated}}">{{formatDate "2006-01-02" .Updated}}</time></li>
+{{end}}</ul>
+</body>
+</html>
+{{end}}
+
+{{define "entry"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{plainText .Entry.Title}}</title>
+</head>
+<body>
+{{with .Entry}}<h1>{{safeContent .Title}}</h1>
+<p><time datetime="{{formatDate "2006-01-02T15:04:05Z07:00" .Updated}}">{{formatDate "2006-01-02" .Updated}}</time></p>
+{{if .Content}}{{safeContent .Content}}{{else}}{{safeContent .Summary}}{{end}}{{end}}
+</body>
+</html>
+{{end}}
+`
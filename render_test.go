@@ -0,0 +1,108 @@
+package atomfeed
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedRenderHTML(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "blog")
+	hello := NewEntry(NewEntryID(feedID, now), "Hello <World>", "/hello/", nil, now, now, []string{"go"}, nil, []byte("<p>Our very first post.</p>"))
+	text := NewEntry(NewEntryID(feedID, now.Add(time.Hour)), "Plain", "https://example.com/plain/", nil, now, now, nil, nil, nil)
+	text.Content = &Content{Type: "text", Value: "1 < 2"}
+	feed := NewFeed(feedID, NewPerson("Go Pher", "", ""), "example.com blog", "", "https://example.com/", "https://example.com/feed.atom", now, []Entry{hello, text})
+
+	out := &bytes.Buffer{}
+	if err := feed.RenderHTML(out, DefaultTheme); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<title>example.com blog</title>`,
+		`<link rel="alternate" type="application/atom+xml" href="https://example.com/feed.atom">`,
+		`<h2><a href="https://example.com/hello/">Hello &lt;World&gt;</a></h2>`,
+		`<time datetime="2017-12-21T08:30:15Z">21 December 2017</time> · go`,
+		`<div><p>Our very first post.</p></div>`,
+		`<div>1 &lt; 2</div>`,
+	} {
+		if strings.Contains(out.String(), want) == false {
+			t.Errorf("RenderHTML() output is missing %s\n\ngot:\n%v", want, out)
+		}
+	}
+
+	out.Reset()
+	if err := feed.RenderEntryHTML(out, MinimalTheme, &feed.Entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<title>Hello &lt;World&gt;</title>`, `<h1>Hello &lt;World&gt;</h1>`, `<p>Our very first post.</p>`} {
+		if strings.Contains(out.String(), want) == false {
+			t.Errorf("RenderEntryHTML() output is missing %s\n\ngot:\n%v", want, out)
+		}
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	theme, err := NewTheme("custom", `{{define "feed"}}{{.Updated | formatDate "2006"}}{{end}}{{define "entry"}}{{plainText .Entry.Title}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := Feed{Updated: NewDate(time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC))}
+	out := &bytes.Buffer{}
+	if err := feed.RenderHTML(out, theme); err != nil || out.String() != "2017" {
+		t.Errorf("RenderHTML() = %q, %v, want 2017", out, err)
+	}
+	if _, err := NewTheme("incomplete", `{{define "feed"}}{{end}}`); err == nil {
+		t.Error("expected an error on missing entry template, got none")
+	}
+}
+
+func Test_safeContent(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  template.HTML
+	}{
+		{"nil", nil, ""},
+		{"nil content", (*Content)(nil), ""},
+		{"text", &TextConstruct{Value: "Fish & Chips"}, "Fish &amp; Chips"},
+		{"html", &TextConstruct{Type: "html", Value: "<em>Fish</em>"}, "<em>Fish</em>"},
+		{"xhtml", &TextConstruct{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml"><em>Fish</em></div>`}, "<em>Fish</em>"},
+		{"text content", &Content{Type: "text/plain", Value: "<em>"}, "&lt;em&gt;"},
+		{"out-of-line content", &Content{Type: "video/mp4", Source: "https://example.com/a.mp4"}, `<a href="https://example.com/a.mp4">https://example.com/a.mp4</a>`},
+		{"base64 content", &Content{Type: "image/png", Value: "iVBORw0KGgo=", base64Encoded: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := safeContent(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("safeContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := safeContent("text"); err == nil {
+		t.Error("expected an error on unsupported type, got none")
+	}
+}
+
+func Test_absURL(t *testing.T) {
+	tests := []struct {
+		base, ref, want string
+	}{
+		{"https://example.com/blog/", "2017/hello/", "https://example.com/blog/2017/hello/"},
+		{"https://example.com/blog/", "/about", "https://example.com/about"},
+		{"https://example.com/blog/", "https://golang.org/", "https://golang.org/"},
+		{"", "/about", "/about"},
+		{"https://example.com/", "%zz", "%zz"},
+	}
+	for _, tt := range tests {
+		if got := absURL(tt.base, tt.ref); got != tt.want {
+			t.Errorf("absURL(%q, %q) = %q, want %q", tt.base, tt.ref, got, tt.want)
+		}
+	}
+}
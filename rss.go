@@ -0,0 +1,489 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// EncodeRSS writes the RSS 2.0 encoding of Feed to the stream.
+//
+// RSS is less expressive than Atom, therefore some information
+// (like contributors) is not part of the output.
+// Authors without email address are encoded as dc:creator elements.
+//  https://www.rssboard.org/rss-specification
+func (f *Feed) EncodeRSS(w io.Writer) error {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return enc.Encode(f.rss())
+}
+
+type rss struct {
+	XMLName    xml.Name   `xml:"rss"`
+	Version    string     `xml:"version,attr"`
+	Namespaces []xml.Attr `xml:",any,attr"`
+	Channel    rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string        `xml:"title"`
+	Link           string        `xml:"link"`
+	Description    string        `xml:"description"`
+	Language       string        `xml:"language,omitempty"`
+	Copyright      string        `xml:"copyright,omitempty"`
+	ManagingEditor string        `xml:"managingEditor,omitempty"`
+	LastBuildDate  string        `xml:"lastBuildDate,omitempty"`
+	Categories     []rssCategory `xml:"category"`
+	Generator      string        `xml:"generator,omitempty"`
+	Image          *rssImage     `xml:"image"`
+	AtomLinks      []Link        `xml:"atom:link"` // only decoded
+	*Geo
+	*DublinCore
+	Items []rssItem `xml:"item"` // items follow all channel elements
+}
+
+type rssImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title,omitempty"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Author      string        `xml:"author,omitempty"`
+	Categories  []rssCategory `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+	GUID        *rssGUID      `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Content     string        `xml:"content:encoded,omitempty"` // only decoded
+	*Media
+	*Geo
+	*DublinCore
+}
+
+type rssCategory struct {
+	Domain string `xml:"domain,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (f *Feed) rss() *rss {
+	channel := rssChannel{
+		Title:          plainText(f.Title),
+		Link:           linkHref(f.Links, "alternate"),
+		Description:    plainText(f.Subtitle),
+		Copyright:      plainText(f.Copyright),
+		ManagingEditor: rssPerson(f.Author),
+		LastBuildDate:  rssDate(f.Updated),
+		Categories:     rssCategories(f.Categories),
+		Geo:            f.Geo,
+		DublinCore:     f.DublinCore,
+	}
+	if channel.Description == "" {
+		channel.Description = channel.Title // description is mandatory
+	}
+	if f.CommonAttributes != nil {
+		channel.Language = f.Lang
+	}
+	if f.Generator != nil {
+		channel.Generator = f.Generator.Value
+	}
+	if channel.ManagingEditor == "" && f.Author != nil && f.Author.Name != "" {
+		channel.DublinCore = channel.DublinCore.withCreator(f.Author.Name)
+	}
+	if f.Logo != nil {
+		channel.Image = &rssImage{URL: f.Logo.Value, Title: channel.Title, Link: channel.Link}
+	}
+	namespaces := f.namespaceAttrs()
+	usesDC := channel.DublinCore.usesElements()
+	for _, e := range f.Entries {
+		item := e.rss()
+		usesDC = usesDC || item.DublinCore.usesElements()
+		channel.Items = append(channel.Items, item)
+	}
+	if usesDC && usesDublinCore(f) == false { // fallback for authors without email address
+		namespaces = append(namespaces, xml.Attr{Name: xml.Name{Local: "xmlns:dc"}, Value: nsDublinCore})
+	}
+	return &rss{Version: "2.0", Namespaces: namespaces, Channel: channel}
+}
+
+func (e *Entry) rss() rssItem {
+	item := rssItem{
+		Title:      plainText(e.Title),
+		Link:       linkHref(e.Links, "alternate"),
+		Author:     rssPerson(e.Author),
+		Categories: rssCategories(e.Categories),
+		PubDate:    rssDate(e.Published),
+		Media:      e.Media,
+		Geo:        e.Geo,
+		DublinCore: e.DublinCore,
+	}
+	if item.Author == "" && e.Author != nil && e.Author.Name != "" {
+		// RSS requires an email address within author, fall back to dc:creator
+		item.DublinCore = item.DublinCore.withCreator(e.Author.Name)
+	}
+	if item.PubDate == "" {
+		item.PubDate = rssDate(e.Updated)
+	}
+	if item.Description = rssDescription(e.Summary); item.Description == "" {
+		item.Description = rssDescription(e.Content)
+	}
+	if e.ID.Value != "" {
+		item.GUID = &rssGUID{Value: e.ID.Value}
+	}
+	for _, l := range e.Links {
+		if l.Rel == "enclosure" { // RSS allows only one enclosure per item
+			item.Enclosure = &rssEnclosure{URL: l.Href, Length: l.Length, Type: l.Type}
+			if item.Enclosure.Length == "" {
+				item.Enclosure.Length = "0"
+			}
+			break
+		}
+	}
+	return item
+}
+
+// linkHref returns the href of the first link with the given relation type.
+// Links without rel attribute are treated as "alternate" links.
+func linkHref(links []Link, rel string) string {
+	for _, l := range links {
+		if l.Rel == rel || (l.Rel == "" && rel == "alternate") {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// rssPerson returns the RSS representation of a person, which requires an email address.
+func rssPerson(p *Person) string {
+	if p == nil || p.Email == "" {
+		return ""
+	}
+	if p.Name == "" {
+		return p.Email
+	}
+	return p.Email + " (" + p.Name + ")"
+}
+
+// rssDate converts a date into the RFC 822 format used by RSS.
+func rssDate(d *Date) string {
+	if d.Time().IsZero() {
+		return ""
+	}
+	return d.Time().Format(time.RFC1123Z)
+}
+
+func rssCategories(categories []Category) []rssCategory {
+	cat := []rssCategory{}
+	for _, c := range categories {
+		cat = append(cat, rssCategory{Domain: c.Scheme, Value: c.Term})
+	}
+	return cat
+}
+
+// rssDescription returns the content as HTML, which is the format of RSS descriptions.
+func rssDescription(c *Content) string {
+	if c == nil || c.Source != "" {
+		return ""
+	}
+	switch c.Type {
+	case "", "text":
+		return html.EscapeString(c.Value)
+	case "html":
+		return c.Value
+	case "xhtml":
+		return UnwrapXHTML(c.ValueXML)
+	}
+	return ""
+}
+
+// DecodeRSS reads a RSS 2.0 document from the stream and converts it into an atom feed.
+//
+// Information of the RSS document, which has no exact Atom equivalent, is reported
+// as conversion losses, e.g. items without guid, which receive their link as ID.
+// Items with content:encoded use it as html content and their description as summary.
+func DecodeRSS(r io.Reader) (*Feed, []string, error) {
+	doc := &rss{}
+	if err := xml.NewTokenDecoder(rssReader{prefixReader{xml.NewDecoder(r)}}).Decode(doc); err != nil {
+		return nil, nil, err
+	}
+	losses := []string{}
+	lose := func(format string, args ...interface{}) {
+		losses = append(losses, fmt.Sprintf(format, args...))
+	}
+	c := &doc.Channel
+	f := &Feed{
+		Namespace:  nsAtom,
+		ID:         NewID(c.Link),
+		Title:      &TextConstruct{Value: c.Title},
+		Author:     parseRSSPerson(c.ManagingEditor),
+		Categories: atomCategories(c.Categories),
+		Geo:        c.Geo,
+		DublinCore: c.DublinCore,
+	}
+	for _, l := range c.AtomLinks {
+		if l.Rel == "self" {
+			f.ID = NewID(l.Href)
+		}
+		f.Links = append(f.Links, l)
+	}
+	if f.ID.Value == "" {
+		lose("channel: no link to derive the feed id from")
+	} else {
+		lose("channel: RSS has no feed id, %q is used instead", f.ID.Value)
+	}
+	if c.Link != "" {
+		f.Links = append([]Link{{Rel: "alternate", Type: "text/html", Href: c.Link}}, f.Links...)
+	}
+	if c.Description != "" && c.Description != c.Title {
+		f.Subtitle = &TextConstruct{Type: "html", Value: c.Description}
+	}
+	if c.Language != "" {
+		f.CommonAttributes = &CommonAttributes{Lang: c.Language}
+	}
+	if c.Copyright != "" {
+		f.Copyright = &TextConstruct{Value: c.Copyright}
+	}
+	if c.Generator != "" {
+		f.Generator = &Generator{Value: c.Generator}
+	}
+	if c.Image != nil && c.Image.URL != "" {
+		f.Logo = &Logo{Value: c.Image.URL}
+	}
+	if f.Author == nil && c.DublinCore != nil && len(c.Creators) > 0 {
+		f.Author = &Person{Name: c.Creators[0]}
+	}
+	if c.LastBuildDate != "" {
+		d, err := ParseDate(c.LastBuildDate)
+		if err != nil {
+			lose("channel: lastBuildDate: %v", err)
+		}
+		f.Updated = d
+	}
+	for i, item := range c.Items {
+		e := item.atom()
+		name := item.Title
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if item.Title == "" {
+			lose("item %v: no title", name)
+		}
+		if item.GUID == nil || item.GUID.Value == "" {
+			if e.ID = NewID(item.Link); e.ID.Value == "" {
+				lose("item %v: no guid and no link to derive the entry id from", name)
+			} else {
+				lose("item %v: no guid, link %q is used as id", name, item.Link)
+			}
+		}
+		if item.PubDate != "" {
+			d, err := ParseDate(item.PubDate)
+			if err != nil {
+				lose("item %v: pubDate: %v", name, err)
+			}
+			if e.Published = d; d != nil {
+				e.Updated = NewDate(d.Time()) // RSS has no separate date of the last update
+			}
+		} else {
+			lose("item %v: no pubDate, entry has no updated date", name)
+		}
+		if item.Author != "" && e.Author == nil {
+			lose("item %v: author %q is not a valid email address", name, item.Author)
+		}
+		if e.Updated.Time().After(f.Updated.Time()) && c.LastBuildDate == "" {
+			f.Updated = NewDate(e.Updated.Time())
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	return f, losses, nil
+}
+
+// rssReader is a xml.TokenReader, which prefixes atom elements within RSS documents,
+// so that they do not collide with RSS elements of the same name (like "link").
+type rssReader struct {
+	r xml.TokenReader
+}
+
+func (r rssReader) Token() (xml.Token, error) {
+	t, err := r.r.Token()
+	switch tt := t.(type) {
+	case xml.StartElement:
+		if tt.Name.Space == nsAtom {
+			tt.Name = xml.Name{Local: "atom:" + tt.Name.Local}
+		}
+		return tt, err
+	case xml.EndElement:
+		if tt.Name.Space == nsAtom {
+			tt.Name = xml.Name{Local: "atom:" + tt.Name.Local}
+		}
+		return tt, err
+	}
+	return t, err
+}
+
+func (item *rssItem) atom() Entry {
+	e := Entry{
+		Title:      &TextConstruct{Value: item.Title},
+		Author:     parseRSSPerson(item.Author),
+		Categories: atomCategories(item.Categories),
+		Media:      item.Media,
+		Geo:        item.Geo,
+		DublinCore: item.DublinCore,
+	}
+	if item.GUID != nil {
+		e.ID = NewID(item.GUID.Value)
+	}
+	if item.Link != "" {
+		e.Links = append(e.Links, Link{Rel: "alternate", Type: "text/html", Href: item.Link})
+	}
+	if item.Enclosure != nil {
+		length := item.Enclosure.Length
+		if length == "0" {
+			length = ""
+		}
+		e.Links = append(e.Links, Link{Rel: "enclosure", Href: item.Enclosure.URL, Type: item.Enclosure.Type, Length: length})
+	}
+	if e.Author == nil && item.DublinCore != nil && len(item.Creators) > 0 {
+		e.Author = &Person{Name: item.Creators[0]}
+	}
+	switch {
+	case item.Content != "":
+		e.Content = &Content{Type: "html", Value: item.Content}
+		if item.Description != "" {
+			e.Summary = &Content{Type: "html", Value: item.Description}
+		}
+	case item.Description != "":
+		e.Content = &Content{Type: "html", Value: item.Description}
+	}
+	return e
+}
+
+// parseRSSPerson parses persons in the formats "email (Name)", "Name <email>" and "email".
+// It returns nil if value contains no email address.
+func parseRSSPerson(value string) *Person {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if i := strings.Index(value, " ("); i > 0 && strings.HasSuffix(value, ")") {
+		if checkEmail(value[:i]) == nil {
+			return &Person{Name: value[i+2 : len(value)-1], Email: value[:i]}
+		}
+	}
+	if a, err := mail.ParseAddress(value); err == nil {
+		name := a.Name
+		if name == "" {
+			name = a.Address
+		}
+		return &Person{Name: name, Email: a.Address}
+	}
+	return nil
+}
+
+func atomCategories(categories []rssCategory) []Category {
+	cat := []Category{}
+	for _, c := range categories {
+		cat = append(cat, Category{Term: strings.TrimSpace(c.Value), Scheme: c.Domain})
+	}
+	return cat
+}
+
+// RSSLosses lists information of the feed, which is lost when encoding it as RSS 2.0 (see EncodeRSS).
+func (f *Feed) RSSLosses() []string {
+	losses := []string{}
+	lose := func(format string, args ...interface{}) {
+		losses = append(losses, fmt.Sprintf(format, args...))
+	}
+	if f.ID.Value != "" {
+		lose("feed: RSS has no feed id, %q is dropped", f.ID.Value)
+	}
+	if f.Author != nil && f.Author.URI != "" {
+		lose("feed: author uri %q", f.Author.URI)
+	}
+	if len(f.Contributor) > 0 {
+		lose("feed: %d contributors", len(f.Contributor))
+	}
+	if f.Icon != nil {
+		lose("feed: icon %q", f.Icon.Value)
+	}
+	for _, l := range f.Links {
+		if l.Rel != "" && l.Rel != "alternate" {
+			lose("feed: %v link %q", l.Rel, l.Href)
+		}
+	}
+	for _, e := range f.Entries {
+		name := e.ID.Value
+		if name == "" {
+			name = textValue(e.Title)
+		}
+		lose := func(format string, args ...interface{}) {
+			lose("entry "+name+": "+format, args...)
+		}
+		if e.Published != nil && e.Updated != nil && e.Updated.Time().Equal(e.Published.Time()) == false {
+			lose("updated date %v, only the published date is kept", e.Updated)
+		}
+		if e.Author != nil && e.Author.URI != "" {
+			lose("author uri %q", e.Author.URI)
+		}
+		if len(e.Contributor) > 0 {
+			lose("%d contributors", len(e.Contributor))
+		}
+		if e.Copyright != nil {
+			lose("rights")
+		}
+		if e.Source != nil {
+			lose("source")
+		}
+		enclosures := 0
+		for _, l := range e.Links {
+			switch {
+			case l.Rel == "enclosure" && enclosures == 0:
+				enclosures++
+			case l.Rel == "enclosure":
+				lose("additional enclosure %q, RSS allows a single enclosure per item", l.Href)
+			case l.Rel != "" && l.Rel != "alternate":
+				lose("%v link %q", l.Rel, l.Href)
+			}
+		}
+		if e.Summary != nil && e.Content != nil {
+			lose("content, only the summary is used as description")
+		}
+		description := e.Summary
+		if description == nil {
+			description = e.Content
+		}
+		if c := description; c != nil {
+			switch {
+			case c.Type == "xhtml":
+				lose("xhtml content is downgraded to html")
+			case c.Source != "":
+				lose("out-of-line content %q", c.Source)
+			case isTextContentType(c.Type) == false:
+				lose("content of type %q", c.Type)
+			}
+		}
+		if e.Title != nil && e.Title.Type == "xhtml" {
+			lose("xhtml markup of title")
+		}
+	}
+	return losses
+}
@@ -0,0 +1,158 @@
+package atomfeed
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const rssDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Go Blog</title>
+    <link>https://blog.golang.org/</link>
+    <atom:link href="https://blog.golang.org/feed.rss" rel="self" type="application/rss+xml"/>
+    <description>The Go Programming Language Blog</description>
+    <language>en</language>
+    <managingEditor>gopher@golang.org (Go Pher)</managingEditor>
+    <item>
+      <title>Go 1.10 released</title>
+      <link>https://blog.golang.org/go1.10</link>
+      <guid isPermaLink="false">tag:blog.golang.org,2018:go1.10</guid>
+      <pubDate>Fri, 16 Feb 2018 10:00:00 +0000</pubDate>
+      <category>release</category>
+      <description>Go 1.10 is out.</description>
+      <content:encoded><![CDATA[<p>Go 1.10 is <em>out</em>.</p>]]></content:encoded>
+      <enclosure url="https://blog.golang.org/go1.10.mp3" length="1024" type="audio/mpeg"/>
+    </item>
+    <item>
+      <title>No guid</title>
+      <link>https://blog.golang.org/no-guid</link>
+      <dc:creator>Octo Cat</dc:creator>
+      <pubDate>Thu, 15 Feb 2018 10:00:00 +0000</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+func TestDecodeRSS(t *testing.T) {
+	f, losses, err := DecodeRSS(strings.NewReader(rssDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, noGUID := f.Entries[0], f.Entries[1]
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"feed id", f.ID.Value, "https://blog.golang.org/feed.rss"},
+		{"feed links", len(f.Links), 2},
+		{"feed title", f.Title.Value, "Go Blog"},
+		{"feed author", *f.Author, Person{Name: "Go Pher", Email: "gopher@golang.org"}},
+		{"feed updated", f.Updated.Time().UTC(), time.Date(2018, time.February, 16, 10, 0, 0, 0, time.UTC)},
+		{"feed lang", f.Lang, "en"},
+		{"entry id", release.ID.Value, "tag:blog.golang.org,2018:go1.10"},
+		{"entry content", *release.Content, Content{Type: "html", Value: "<p>Go 1.10 is <em>out</em>.</p>"}},
+		{"entry summary", release.Summary.Value, "Go 1.10 is out."},
+		{"entry enclosure", release.Links[1], Link{Rel: "enclosure", Href: "https://blog.golang.org/go1.10.mp3", Type: "audio/mpeg", Length: "1024"}},
+		{"entry category", release.Categories[0].Term, "release"},
+		{"entry without guid", noGUID.ID.Value, "https://blog.golang.org/no-guid"},
+		{"dc:creator", noGUID.Author.Name, "Octo Cat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("DecodeRSS() %v = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+	wantLosses := []string{
+		`channel: RSS has no feed id, "https://blog.golang.org/feed.rss" is used instead`,
+		`item No guid: no guid, link "https://blog.golang.org/no-guid" is used as id`,
+	}
+	if !reflect.DeepEqual(losses, wantLosses) {
+		t.Errorf("DecodeRSS() losses = %q, want %q", losses, wantLosses)
+	}
+	if err := f.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFeedRSSRoundTrip(t *testing.T) {
+	f, _, err := DecodeRSS(strings.NewReader(rssDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := f.EncodeRSS(out); err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := DecodeRSS(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Entries) != 2 || again.Entries[0].ID != f.Entries[0].ID || again.Entries[1].Author.Name != "Octo Cat" {
+		t.Errorf("DecodeRSS(EncodeRSS()) = %v, want %v", again.Entries, f.Entries)
+	}
+}
+
+func TestFeedEncodeRSSTitles(t *testing.T) {
+	f := Feed{
+		Title:    &TextConstruct{Type: "html", Value: "Go &amp; <em>Gophers</em>"},
+		Subtitle: XHTMLText("<p>News <b>weekly</b></p>"),
+		Entries:  []Entry{{ID: NewID("1"), Title: &TextConstruct{Type: "html", Value: "1 &lt; 2"}}},
+	}
+	out := &bytes.Buffer{}
+	if err := f.EncodeRSS(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<title>Go &amp; Gophers</title>", "<description>News weekly</description>", "<title>1 &lt; 2</title>"} {
+		if strings.Contains(out.String(), want) == false {
+			t.Errorf("EncodeRSS() output is missing %v\n\ngot:\n%v", want, out)
+		}
+	}
+}
+
+func TestFeedRSSLosses(t *testing.T) {
+	xhtml := XHTMLContent([]byte("<p>Hello</p>"))
+	f := Feed{
+		ID:    NewID("tag:example.com,2005:blog"),
+		Links: []Link{{Rel: "alternate", Href: "https://example.com/"}, NewLicense("https://creativecommons.org/licenses/by/4.0/")},
+		Entries: []Entry{
+			{ID: NewID("1"), Content: xhtml, Contributor: []Person{{Name: "Octo Cat"}}},
+			{ID: NewID("2"), Summary: &Content{Type: "text", Value: "summary"}, Content: &Content{Type: "html", Value: "<p>content</p>"}},
+		},
+	}
+	want := []string{
+		`feed: RSS has no feed id, "tag:example.com,2005:blog" is dropped`,
+		`feed: license link "https://creativecommons.org/licenses/by/4.0/"`,
+		`entry 1: 1 contributors`,
+		`entry 1: xhtml content is downgraded to html`,
+		`entry 2: content, only the summary is used as description`,
+	}
+	if got := f.RSSLosses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RSSLosses() = %q, want %q", got, want)
+	}
+}
+
+func Test_rssDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *Content
+		want    string
+	}{
+		{"text", &Content{Type: "text", Value: "a < b"}, "a &lt; b"},
+		{"html", &Content{Type: "html", Value: "<p>Hello</p>"}, "<p>Hello</p>"},
+		{"xhtml", XHTMLContent([]byte("<p>Hello<br>World</p>")), "<p>Hello<br/>World</p>"},
+		{"out-of-line", &Content{Source: "https://example.com/post"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rssDescription(tt.content); got != tt.want {
+				t.Errorf("rssDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+)
+
+// Policy describes which HTML elements and attributes survive sanitization.
+// Use DefaultPolicy for an allowlist similar to the ones used by common feed readers.
+type Policy struct {
+	// Elements maps the allowed elements to their allowed attributes.
+	// Elements which aren't allowed are removed, but their content is kept.
+	Elements map[string][]string
+	// GlobalAttributes are allowed on every allowed element.
+	GlobalAttributes []string
+	// URLSchemes lists the allowed schemes of URL attributes (like href or src).
+	// Relative URLs are always allowed.
+	URLSchemes []string
+	// DropElements are removed together with their content.
+	DropElements []string
+}
+
+// DefaultPolicy returns a policy, which strips scripts, styles, embedded objects,
+// forms, event handlers and URLs with dangerous schemes (like javascript:),
+// while keeping common text formatting, links, images, lists and tables.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Elements: map[string][]string{
+			"a": {"href", "hreflang", "rel", "type"}, "abbr": nil, "acronym": nil, "address": nil,
+			"audio": {"src", "controls"}, "b": nil, "bdi": nil, "bdo": nil, "big": nil,
+			"blockquote": {"cite"}, "br": nil, "caption": nil, "cite": nil, "code": nil,
+			"col": {"span"}, "colgroup": {"span"}, "dd": nil, "del": {"cite", "datetime"},
+			"details": {"open"}, "dfn": nil, "div": nil, "dl": nil, "dt": nil, "em": nil,
+			"figcaption": nil, "figure": nil, "h1": nil, "h2": nil, "h3": nil, "h4": nil,
+			"h5": nil, "h6": nil, "hr": nil, "i": nil, "img": {"src", "alt", "width", "height"},
+			"ins": {"cite", "datetime"}, "kbd": nil, "li": {"value"}, "mark": nil,
+			"ol": {"start", "type", "reversed"}, "p": nil, "picture": nil, "pre": nil, "q": {"cite"},
+			"s": nil, "samp": nil, "small": nil, "source": {"src", "srcset", "type", "media"},
+			"span": nil, "strike": nil, "strong": nil, "sub": nil, "summary": nil, "sup": nil,
+			"table": {"summary"}, "tbody": nil, "td": {"colspan", "rowspan", "headers"},
+			"tfoot": nil, "th": {"colspan", "rowspan", "headers", "scope"}, "thead": nil,
+			"time": {"datetime"}, "tr": nil, "tt": nil, "u": nil, "ul": nil, "var": nil,
+			"video": {"src", "controls", "poster", "width", "height"},
+		},
+		GlobalAttributes: []string{"title", "lang", "dir"},
+		URLSchemes:       []string{"http", "https", "mailto", "ftp"},
+		DropElements: []string{
+			"script", "style", "iframe", "frame", "frameset", "object", "embed", "applet",
+			"noscript", "template", "svg", "math", "form", "textarea", "select", "button",
+			"head", "title",
+		},
+	}
+}
+
+// urlAttributes contain URLs, whose scheme must be allowed by the policy.
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "poster": true, "srcset": true,
+	"action": true, "background": true, "longdesc": true, "formaction": true,
+}
+
+// Sanitize removes all elements and attributes from (possibly malformed) HTML,
+// which aren't allowed by the policy, and returns the result as well-formed markup.
+// Sanitize never fails: markup, which isn't a valid tag, is escaped as text.
+func (p *Policy) Sanitize(html string) string {
+	return writeXHTML(p.sanitize(htmlTokens(html)))
+}
+
+func (p *Policy) sanitize(tokens []xml.Token) []xml.Token {
+	elements := map[string]map[string]bool{}
+	for name, attrs := range p.Elements {
+		elements[name] = toSet(append(append([]string{}, attrs...), p.GlobalAttributes...))
+	}
+	drop := toSet(p.DropElements)
+	schemes := toSet(p.URLSchemes)
+
+	clean := []xml.Token{}
+	kept := []bool{} // whether the start element of every open element was kept
+	dropDepth := 0   // depth within a dropped element
+	for _, t := range tokens {
+		switch t := t.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			foreign := t.Name.Space != "" && t.Name.Space != nsXHTML
+			if dropDepth > 0 || drop[name] {
+				dropDepth++
+				continue
+			}
+			allowed, ok := elements[name]
+			if ok == false || foreign {
+				kept = append(kept, false)
+				continue
+			}
+			attrs := []xml.Attr{}
+			for _, attr := range t.Attr {
+				attrName := strings.ToLower(attr.Name.Local)
+				if attr.Name.Space != "" || allowed[attrName] == false {
+					continue
+				}
+				if urlAttributes[attrName] && isSafeURL(attr.Value, schemes) == false {
+					continue
+				}
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: attrName}, Value: attr.Value})
+			}
+			kept = append(kept, true)
+			clean = append(clean, xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+		case xml.EndElement:
+			if dropDepth > 0 {
+				dropDepth--
+				continue
+			}
+			keep := kept[len(kept)-1]
+			kept = kept[:len(kept)-1]
+			if keep {
+				clean = append(clean, xml.EndElement{Name: xml.Name{Local: strings.ToLower(t.Name.Local)}})
+			}
+		case xml.CharData:
+			if dropDepth == 0 {
+				clean = append(clean, t)
+			}
+		}
+	}
+	return clean
+}
+
+// isSafeURL reports whether the URL is relative or uses one of the allowed schemes.
+// A srcset attribute contains several URLs, which are checked one by one.
+func isSafeURL(value string, schemes map[string]bool) bool {
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		// browsers ignore control characters and whitespace within schemes ("java\tscript:")
+		cleaned := strings.Map(func(r rune) rune {
+			if r <= ' ' || r == 0x7f {
+				return -1
+			}
+			return r
+		}, candidate)
+		u, err := url.Parse(strings.TrimSpace(cleaned))
+		if err != nil {
+			return false
+		}
+		if u.Scheme != "" && schemes[strings.ToLower(u.Scheme)] == false {
+			return false
+		}
+	}
+	return true
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
+
+// Sanitize applies the policy to all html and xhtml text constructs
+// and contents of the feed and its entries.
+// Call Sanitize before Encode when syndicating untrusted content.
+// All text constructs and contents are sanitized, however malformed their markup is.
+func (f *Feed) Sanitize(p *Policy) {
+	for _, t := range []*TextConstruct{f.Title, f.Subtitle, f.Copyright} {
+		p.sanitizeText(t)
+	}
+	for i := range f.Entries {
+		f.Entries[i].Sanitize(p)
+	}
+}
+
+// Sanitize applies the policy to all html and xhtml text constructs and contents of the entry.
+func (e *Entry) Sanitize(p *Policy) {
+	for _, t := range []*TextConstruct{e.Title, e.Copyright} {
+		p.sanitizeText(t)
+	}
+	for _, c := range []*Content{e.Summary, e.Content} {
+		p.sanitizeContent(c)
+	}
+}
+
+func (p *Policy) sanitizeText(t *TextConstruct) {
+	if t == nil {
+		return
+	}
+	switch t.Type {
+	case "html":
+		t.Value = p.Sanitize(t.Value)
+	case "xhtml":
+		t.ValueXML = WrapXHTML(p.Sanitize(UnwrapXHTML(t.ValueXML)))
+	}
+}
+
+func (p *Policy) sanitizeContent(c *Content) {
+	if c == nil {
+		return
+	}
+	switch c.Type {
+	case "html":
+		c.Value = p.Sanitize(c.Value)
+	case "xhtml":
+		c.ValueXML = WrapXHTML(p.Sanitize(UnwrapXHTML(c.ValueXML)))
+	}
+}
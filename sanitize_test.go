@@ -0,0 +1,80 @@
+package atomfeed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicySanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"harmless", `<p>Go <em>go</em> <a href="https://golang.org/" title="Go">go</a></p>`, `<p>Go <em>go</em> <a href="https://golang.org/" title="Go">go</a></p>`},
+		{"script", `<p>Hello</p><script>alert("pwned")</script>`, `<p>Hello</p>`},
+		{"style", `<style>body { display: none }</style>Hello`, `Hello`},
+		{"event handler", `<img src="gopher.png" onerror="alert(1)" ONLOAD="alert(2)" alt="Go Pher">`, `<img src="gopher.png" alt="Go Pher"/>`},
+		{"javascript url", `<a href="javascript:alert(1)">click</a>`, `<a>click</a>`},
+		{"obfuscated javascript url", `<a href=" JaVa&#x09;ScRiPt:alert(1)">click</a>`, `<a>click</a>`},
+		{"data url", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, `<img/>`},
+		{"relative url", `<a href="/post/1">post</a>`, `<a href="/post/1">post</a>`},
+		{"srcset", `<source srcset="small.jpg 1x, javascript:alert(1) 2x">`, `<source/>`},
+		{"unknown element keeps content", `<marquee>Go <blink>go</blink></marquee>`, `Go go`},
+		{"nested drop", `<iframe src="https://evil.example.com"><p>fallback</p></iframe>text`, `text`},
+		{"inline svg", `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>ok`, `ok`},
+		{"style attribute", `<p style="background: url(javascript:alert(1))">Hello</p>`, `<p>Hello</p>`},
+		{"comment", `Hello<!--[if IE]><script>alert(1)</script><![endif]-->`, `Hello`},
+		{"malformed", `<P CLASS=x>Hello<b>World`, `<p>Hello<b>World</b></p>`},
+		{"bare less-than", `a < b<script>alert(1)</script>`, `a &lt; b`},
+		{"unquoted event handler", `<img src=x.png onerror=alert(1)>`, `<img src="x.png"/>`},
+		{"unterminated script", `Hello<script>alert(1)`, `Hello`},
+	}
+	policy := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Sanitize(tt.html); got != tt.want {
+				t.Errorf("Sanitize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedSanitize(t *testing.T) {
+	now := time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewID("tag:example.com,2012-12-21:blog")
+	entry := NewEntry(NewEntryID(feedID, now), "Article 1", "https://example.com/blog/1", nil, now, now, nil, []byte(`<em onclick="steal()">summary</em>`), []byte(`<h1>Header 1</h1><script>steal()</script>`))
+	xhtml := XHTMLContent([]byte(`<p>Hello<object data="evil.swf"></object></p>`))
+	plain := NewContent("text", "", []byte(`<script>text is not html</script>`))
+	feed := NewFeed(feedID, NewPerson("Go Pher", "", ""), "<b>Blog</b>", "", "https://example.com", "https://example.com/feed.atom", now, []Entry{entry, {ID: NewID("tag:example.com,2012-12-21:blog.post-2"), Content: xhtml, Summary: plain}})
+	feed.Subtitle = &TextConstruct{Type: "html", Value: `Go <img src="x" onerror="steal()">`}
+
+	policy := &Policy{Elements: map[string][]string{"p": nil, "em": nil, "img": {"src"}}}
+	feed.Sanitize(policy)
+	checks := []struct{ got, want string }{
+		{feed.Title.Value, "<b>Blog</b>"}, // type text is never touched
+		{feed.Subtitle.Value, `Go <img src="x"/>`},
+		{feed.Entries[0].Summary.Value, "<em>summary</em>"},
+		{feed.Entries[0].Content.Value, "Header 1steal()"}, // custom policy without drop elements
+		{feed.Entries[1].Content.ValueXML, `<div xmlns="http://www.w3.org/1999/xhtml"><p>Hello</p></div>`},
+		{feed.Entries[1].Summary.Value, `<script>text is not html</script>`},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("Sanitize() = %v, want %v", c.got, c.want)
+		}
+	}
+}
+
+func TestFeedSanitizeMalformed(t *testing.T) {
+	feed := Feed{Entries: []Entry{
+		{ID: NewID("tag:example.com,2012-12-21:blog.post-1"), Content: NewContent("html", "", []byte(`a < b <img src=x onerror=alert(1)>`))},
+		{ID: NewID("tag:example.com,2012-12-21:blog.post-2"), Content: NewContent("html", "", []byte(`<p>ok<script>alert(2)</script>`))},
+	}}
+	feed.Sanitize(DefaultPolicy())
+	for i, want := range []string{`a &lt; b <img src="x"/>`, `<p>ok</p>`} {
+		if got := feed.Entries[i].Content.Value; got != want {
+			t.Errorf("Sanitize() entry %d = %v, want %v", i, got, want)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package atomfeed
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 // Feed is an atom:feed element and is the document (i.e., top-level) element of
 // an Atom Feed Document, acting as a container for metadata and data associated with the feed.
 //  https://tools.ietf.org/html/rfc4287#section-4.1.1
 type Feed struct {
 	XMLName     xml.Name       `xml:"feed"`
-	Namespace   string         `xml:"xmlns,attr"` // xmlns="http://www.w3.org/2005/Atom"
+	Namespace   string         `xml:"xmlns,attr,omitempty"` // xmlns="http://www.w3.org/2005/Atom"
 	ID          ID             `xml:"id"`
 	Generator   *Generator     `xml:"generator"`
 	Links       []Link         `xml:"link"`
@@ -22,6 +25,11 @@ type Feed struct {
 	Copyright   *TextConstruct `xml:"rights"` // https://tools.ietf.org/html/rfc4287#section-4.2.10
 	Entries     []Entry        `xml:"entry"`
 	*CommonAttributes
+	*Geo        // GeoRSS extension
+	*DublinCore // Dublin Core extension
+	*OpenSearch // OpenSearch response elements
+	// Stylesheet is an optional xml-stylesheet processing instruction, which is written by Encode.
+	Stylesheet *Stylesheet `xml:"-"`
 }
 
 // Entry is an atom:entry element and represents an individual entry, acting as a
@@ -41,6 +49,9 @@ type Entry struct {
 	Summary     *Content       `xml:"summary"`
 	Content     *Content       `xml:"content"`
 	*CommonAttributes
+	*Media      // Media RSS extension
+	*Geo        // GeoRSS extension
+	*DublinCore // Dublin Core extension
 }
 
 // Source is an atom:source element.
@@ -84,6 +95,9 @@ type TextConstruct struct {
 	// https://tools.ietf.org/html/rfc4287#section-3.1.1
 	Type  string `xml:"type,attr,omitempty"`
 	Value string `xml:",chardata"`
+	// ValueXML contains the XHTML div element when using type "xhtml".
+	// https://tools.ietf.org/html/rfc4287#section-3.1.1.3
+	ValueXML string `xml:",innerxml"`
 	*CommonAttributes
 }
 
@@ -98,11 +112,16 @@ type Person struct {
 }
 
 // Date is an atom:date element whose content MUST conform to the "date-time" format defined in [RFC3339].
+// Use NewDate or ParseDate to create a date and Time to access it.
 //  https://tools.ietf.org/html/rfc4287#section-3.3
 type Date struct {
-	// Value must be a date conforming to RFC3339.
-	// Try: `time.Now().Format(time.RFC3339)`
+	// Value is the text of the date element, as decoded or formatted in RFC 3339.
+	// Decode keeps invalid dates, which are reported by Verify, in Value.
+	//
+	// Deprecated: Use Time and Set. Value is only parsed,
+	// if the date was created without time (like Date{Value: "2012-12-21T08:30:15Z"}).
 	Value string `xml:",chardata"`
+	t     time.Time
 	*CommonAttributes
 }
 
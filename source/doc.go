@@ -0,0 +1,20 @@
+/*
+Package source builds atom entries from a directory of Markdown (or HTML) posts with front matter,
+so that static sites can publish a feed straight from their repository without a site generator.
+
+	entries, err := source.Walk("content/posts", source.Config{
+		FeedID:  atomfeed.NewID("tag:example.com,2005-12-21:blog"),
+		BaseURL: "https://example.com/posts/",
+		Author:  atomfeed.NewPerson("Go Pher", "", ""),
+	})
+
+Posts start with YAML ("---") or TOML ("+++") front matter:
+
+	---
+	title: Hello World
+	date: 2017-12-21T08:30:15Z
+	tags: [go, web]
+	---
+	# Hello *World*
+*/
+package source // import "github.com/denisbrodbeck/atomfeed/source"
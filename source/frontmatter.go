@@ -0,0 +1,177 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+// FrontMatter holds the metadata of a post.
+type FrontMatter struct {
+	ID      string    // optional stable ID, which replaces the ID derived from Date, e.g. for posts of the same date and time
+	Title   string    //
+	Date    time.Time // publication date
+	Updated time.Time // optional date of the last update, defaults to Date
+	Tags    []string  // also read from "categories"
+	Author  string    //
+	Summary string    // also read from "description"
+	Draft   bool      // drafts are skipped by Walk
+}
+
+// ParseFrontMatter splits a post into its front matter and its body.
+// YAML front matter is delimited by "---" lines, TOML front matter by "+++" lines.
+// Posts without front matter return an empty FrontMatter and the unchanged data.
+//
+// Only the flat subset of YAML and TOML used by static site generators is supported:
+// strings, dates, booleans and lists of strings. Unknown keys are ignored.
+func ParseFrontMatter(data []byte) (FrontMatter, []byte, error) {
+	fm := FrontMatter{}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // byte order mark
+	text := strings.Replace(string(data), "\r\n", "\n", -1)
+	delimiter := ""
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delimiter = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delimiter = "+++"
+	default:
+		return fm, data, nil
+	}
+	lines := strings.Split(text, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == delimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return fm, nil, fmt.Errorf("front matter: missing closing %q", delimiter)
+	}
+	values, err := parseValues(lines[1:end], delimiter == "+++", 2)
+	if err != nil {
+		return fm, nil, fmt.Errorf("front matter: %v", err)
+	}
+	if err := fm.set(values); err != nil {
+		return fm, nil, err
+	}
+	return fm, []byte(strings.Join(lines[end+1:], "\n")), nil
+}
+
+// ParseYAML parses a YAML document of the flat subset supported by front matter (see ParseFrontMatter),
+// e.g. a configuration file. Keys are lowercased and each value is returned as a list of strings.
+func ParseYAML(data []byte) (map[string][]string, error) {
+	text := strings.Replace(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n", -1)
+	return parseValues(strings.Split(text, "\n"), false, 1)
+}
+
+// parseValues parses "key: value" (YAML) or "key = value" (TOML) lines,
+// which start at line number first. Each value is returned as a list of strings.
+func parseValues(lines []string, toml bool, first int) (map[string][]string, error) {
+	values := map[string][]string{}
+	separator := ":"
+	if toml {
+		separator = "="
+	}
+	key := "" // key of a YAML block list
+	for n, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if toml == false && strings.HasPrefix(trimmed, "- ") && key != "" {
+			values[key] = append(values[key], unquote(stripComment(trimmed[2:])))
+			continue
+		}
+		i := strings.Index(line, separator)
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key%svalue, got %q", n+first, separator, trimmed)
+		}
+		key = strings.ToLower(strings.TrimSpace(line[:i]))
+		value := stripComment(strings.TrimSpace(line[i+1:]))
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			list := []string{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = unquote(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			values[key] = list
+			continue
+		}
+		values[key] = nil
+		if value != "" {
+			values[key] = []string{unquote(value)}
+		}
+	}
+	return values, nil
+}
+
+// stripComment removes a trailing comment from an unquoted value.
+func stripComment(value string) string {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		return value
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1)
+	}
+	return value
+}
+
+func (fm *FrontMatter) set(values map[string][]string) error {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if v := values[key]; len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+	date := func(keys ...string) (time.Time, error) {
+		value := first(keys...)
+		if value == "" {
+			return time.Time{}, nil
+		}
+		d, err := atomfeed.ParseDate(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("front matter: %v", err)
+		}
+		return d.Time(), nil
+	}
+	var err error
+	fm.ID = first("id")
+	fm.Title = first("title")
+	fm.Author = first("author")
+	fm.Summary = first("summary", "description")
+	fm.Tags = values["tags"]
+	if fm.Tags == nil {
+		fm.Tags = values["categories"]
+	}
+	if draft := first("draft"); draft != "" {
+		if fm.Draft, err = strconv.ParseBool(draft); err != nil {
+			return fmt.Errorf("front matter: invalid draft value %q", draft)
+		}
+	}
+	if fm.Date, err = date("date"); err != nil {
+		return err
+	}
+	fm.Updated, err = date("updated", "lastmod")
+	return err
+}
@@ -0,0 +1,85 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	date := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name     string
+		data     string
+		want     FrontMatter
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name: "yaml",
+			data: "---\ntitle: \"Hello: World\"\ndate: 2017-12-21T08:30:15Z\nupdated: 2017-12-22\ntags:\n  - go\n  - 'web dev'\nauthor: Go Pher # comment\ndescription: A greeting\nid: tag:example.com,2005:hello\ndraft: false\n---\n# Body\n",
+			want: FrontMatter{
+				ID:      "tag:example.com,2005:hello",
+				Title:   "Hello: World",
+				Date:    date,
+				Updated: time.Date(2017, time.December, 22, 0, 0, 0, 0, time.UTC),
+				Tags:    []string{"go", "web dev"},
+				Author:  "Go Pher",
+				Summary: "A greeting",
+			},
+			wantBody: "# Body\n",
+		},
+		{
+			name:     "toml",
+			data:     "+++\ntitle = \"Hello\"\ndate = 2017-12-21T08:30:15Z\ncategories = [\"go\", \"web\"]\ndraft = true\n+++\nBody",
+			want:     FrontMatter{Title: "Hello", Date: date, Tags: []string{"go", "web"}, Draft: true},
+			wantBody: "Body",
+		},
+		{
+			name:     "inline yaml list and crlf",
+			data:     "---\r\ntitle: Hello\r\ntags: [go, web]\r\n---\r\nBody",
+			want:     FrontMatter{Title: "Hello", Tags: []string{"go", "web"}},
+			wantBody: "Body",
+		},
+		{
+			name:     "no front matter",
+			data:     "# Just Markdown",
+			want:     FrontMatter{},
+			wantBody: "# Just Markdown",
+		},
+		{"unclosed", "---\ntitle: Hello\n", FrontMatter{}, "", true},
+		{"invalid date", "---\ndate: yesterday\n---\n", FrontMatter{}, "", true},
+		{"invalid line", "---\njust text\n---\n", FrontMatter{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, body, err := ParseFrontMatter([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFrontMatter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFrontMatter() = %+v, want %+v", got, tt.want)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("ParseFrontMatter() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	got, err := ParseYAML([]byte("# feed\nTitle: Blog\nauthors:\n  - Go Pher\n  - Octo Cat\npage_size: 10 # entries per page\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"title": {"Blog"}, "authors": {"Go Pher", "Octo Cat"}, "page_size": {"10"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseYAML() = %v, want %v", got, want)
+	}
+	if _, err := ParseYAML([]byte("title: Blog\noops\n")); err == nil || err.Error() != `line 2: expected key:value, got "oops"` {
+		t.Errorf("ParseYAML() error = %v, want error in line 2", err)
+	}
+}
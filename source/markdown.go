@@ -0,0 +1,406 @@
+package source
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown renders Markdown to HTML.
+//
+// The renderer supports the commonly used subset of CommonMark: ATX and setext headings,
+// paragraphs, hard line breaks, emphasis, strong emphasis, code spans, fenced and indented code blocks,
+// block quotes, ordered and unordered (nested) lists, thematic breaks, links, images,
+// autolinks and raw HTML. Reference links and tables are not supported.
+//  https://spec.commonmark.org/
+func Markdown(markdown []byte) []byte {
+	text := strings.Replace(string(markdown), "\r\n", "\n", -1)
+	text = strings.Replace(text, "\t", "    ", -1)
+	b := &strings.Builder{}
+	renderBlocks(b, strings.Split(text, "\n"))
+	return []byte(b.String())
+}
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}((?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	codeFence     = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	listMarker    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])( +|$)`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	htmlBlock     = regexp.MustCompile(`^ {0,3}</?(?i:address|article|aside|blockquote|details|div|dl|fieldset|figcaption|figure|footer|form|h[1-6]|header|hr|iframe|main|nav|ol|p|pre|section|table|ul|video|audio|script|style)(?:[\s/>]|$)|^ {0,3}<!--`)
+)
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// renderBlocks renders the block structure of lines.
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case atxHeading.MatchString(line):
+			m := atxHeading.FindStringSubmatch(line)
+			level := string('0' + rune(len(m[1])))
+			b.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+			i++
+		case thematicBreak.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case codeFence.MatchString(line):
+			i = renderFencedCode(b, lines, i)
+		case strings.HasPrefix(line, "    "):
+			i = renderIndentedCode(b, lines, i)
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			i = renderBlockQuote(b, lines, i)
+		case listMarker.MatchString(line):
+			i = renderList(b, lines, i)
+		case htmlBlock.MatchString(line):
+			for ; i < len(lines) && isBlank(lines[i]) == false; i++ {
+				b.WriteString(lines[i] + "\n")
+			}
+		default:
+			i = renderParagraph(b, lines, i)
+		}
+	}
+}
+
+// startsBlock reports whether line interrupts a paragraph.
+func startsBlock(line string) bool {
+	return isBlank(line) ||
+		atxHeading.MatchString(line) ||
+		thematicBreak.MatchString(line) ||
+		codeFence.MatchString(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">") ||
+		listMarker.MatchString(line) && isBlank(listMarker.ReplaceAllString(line, "")) == false ||
+		htmlBlock.MatchString(line)
+}
+
+func renderParagraph(b *strings.Builder, lines []string, i int) int {
+	paragraph := []string{strings.TrimLeft(lines[i], " ")}
+	for i++; i < len(lines); i++ {
+		if m := setextLine.FindStringSubmatch(lines[i]); m != nil {
+			level := "1"
+			if m[1][0] == '-' {
+				level = "2"
+			}
+			b.WriteString("<h" + level + ">" + renderInline(strings.TrimSpace(strings.Join(paragraph, "\n"))) + "</h" + level + ">\n")
+			return i + 1
+		}
+		if startsBlock(lines[i]) {
+			break
+		}
+		paragraph = append(paragraph, strings.TrimLeft(lines[i], " "))
+	}
+	b.WriteString("<p>" + renderInline(strings.TrimRight(strings.Join(paragraph, "\n"), " ")) + "</p>\n")
+	return i
+}
+
+func renderFencedCode(b *strings.Builder, lines []string, i int) int {
+	m := codeFence.FindStringSubmatch(lines[i])
+	indent, fence, lang := len(m[1]), m[2], m[3]
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+	}
+	b.WriteString(">")
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		b.WriteString(html.EscapeString(line) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderIndentedCode(b *strings.Builder, lines []string, i int) int {
+	code := []string{}
+	for ; i < len(lines) && (strings.HasPrefix(lines[i], "    ") || isBlank(lines[i])); i++ {
+		code = append(code, strings.TrimPrefix(lines[i], "    "))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+	return i
+}
+
+func renderBlockQuote(b *strings.Builder, lines []string, i int) int {
+	quote := []string{}
+	for ; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " ")
+		if strings.HasPrefix(line, ">") {
+			line = strings.TrimPrefix(line[1:], " ")
+		} else if isBlank(line) || len(quote) == 0 || startsBlock(line) || isBlank(quote[len(quote)-1]) {
+			break // lazy continuation lines only continue paragraphs
+		}
+		quote = append(quote, line)
+	}
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, quote)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+func renderList(b *strings.Builder, lines []string, i int) int {
+	m := listMarker.FindStringSubmatch(lines[i])
+	ordered := strings.ContainsAny(m[2][len(m[2])-1:], ".)")
+	delimiter := m[2][len(m[2])-1:]
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if start := strings.TrimLeft(m[2][:len(m[2])-1], "0"); start != "1" {
+			if start == "" {
+				start = "0"
+			}
+			tag = `ol start="` + start + `"`
+		}
+	}
+	items := [][]string{}
+	tight := true
+	for i < len(lines) {
+		m := listMarker.FindStringSubmatch(lines[i])
+		if m == nil || m[2][len(m[2])-1:] != delimiter || (ordered != strings.ContainsAny(delimiter, ".)")) {
+			break
+		}
+		indent := len(m[0])
+		if isBlank(m[3]) == false && len(m[3]) > 4 {
+			indent = len(m[1]) + len(m[2]) + 1 // content starting with indented code
+		}
+		item := []string{lines[i][indent:]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case isBlank(line):
+				item = append(item, "")
+				continue
+			case strings.HasPrefix(line, strings.Repeat(" ", indent)):
+				item = append(item, line[indent:])
+				continue
+			case isBlank(item[len(item)-1]) == false && startsBlock(line) == false:
+				item = append(item, strings.TrimLeft(line, " ")) // lazy continuation
+				continue
+			}
+			break
+		}
+		// trailing blank lines belong to the list, not to the item
+		blank := 0
+		for len(item) > 1 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			blank++
+		}
+		for _, line := range item {
+			if isBlank(line) {
+				tight = false
+			}
+		}
+		items = append(items, item)
+		if blank > 0 {
+			if i < len(lines) && listMarker.MatchString(lines[i]) {
+				tight = false
+				continue
+			}
+			break
+		}
+	}
+	b.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		inner := &strings.Builder{}
+		renderBlocks(inner, item)
+		content := inner.String()
+		if tight {
+			content = strings.Replace(strings.Replace(content, "<p>", "", -1), "</p>\n", "\n", -1)
+		}
+		b.WriteString("<li>" + strings.TrimSuffix(content, "\n") + "</li>\n")
+	}
+	b.WriteString("</" + strings.Fields(tag)[0] + ">\n")
+	return i
+}
+
+var (
+	inlineLink = regexp.MustCompile(`^\[((?:[^\[\]\\]|\\.|\[[^\[\]]*\])*)\]\(\s*(<[^>]*>|[^\s()]*(?:\([^\s()]*\)[^\s()]*)*)(?:\s+("[^"]*"|'[^']*'))?\s*\)`)
+	autoLink   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailLink  = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+	rawHTML    = regexp.MustCompile(`^(?:<[a-zA-Z][a-zA-Z0-9-]*(?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>|</[a-zA-Z][a-zA-Z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+	entity     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// renderInline renders the inline elements of a paragraph or heading.
+func renderInline(text string) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!<>\"'&~|", text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+		case c == '\n':
+			if strings.HasSuffix(b.String(), "  ") {
+				trimmed := strings.TrimRight(b.String(), " ")
+				b.Reset()
+				b.WriteString(trimmed + "<br>")
+			}
+			b.WriteString("\n")
+			i++
+		case c == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:n]
+			end := strings.Index(rest[n:], fence)
+			for end >= 0 && n+end+n < len(rest) && rest[n+end+n] == '`' {
+				next := strings.Index(rest[n+end+n:], fence)
+				if next < 0 {
+					end = -1
+					break
+				}
+				end += n + next
+			}
+			if end < 0 {
+				b.WriteString(fence)
+				i += n
+				break
+			}
+			code := strings.Replace(rest[n:n+end], "\n", " ", -1)
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			i += n + end + n
+		case c == '*' || c == '_':
+			n := renderEmphasis(b, text, i)
+			i += n
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if m := inlineLink.FindStringSubmatch(rest[1:]); m != nil {
+				b.WriteString(`<img src="` + html.EscapeString(linkDestination(m[2])) + `" alt="` + html.EscapeString(plainText(m[1])) + `"`)
+				if m[3] != "" {
+					b.WriteString(` title="` + html.EscapeString(m[3][1:len(m[3])-1]) + `"`)
+				}
+				b.WriteString(">")
+				i += 1 + len(m[0])
+				break
+			}
+			b.WriteString("!")
+			i++
+		case c == '[':
+			if m := inlineLink.FindStringSubmatch(rest); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(linkDestination(m[2])) + `"`)
+				if m[3] != "" {
+					b.WriteString(` title="` + html.EscapeString(m[3][1:len(m[3])-1]) + `"`)
+				}
+				b.WriteString(">" + renderInline(m[1]) + "</a>")
+				i += len(m[0])
+				break
+			}
+			b.WriteString("[")
+			i++
+		case c == '<':
+			if m := autoLink.FindStringSubmatch(rest); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+			} else if m := emailLink.FindStringSubmatch(rest); m != nil {
+				b.WriteString(`<a href="mailto:` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+			} else if m := rawHTML.FindString(rest); m != "" {
+				b.WriteString(m)
+				i += len(m)
+			} else {
+				b.WriteString("&lt;")
+				i++
+			}
+		case c == '&':
+			if m := entity.FindString(rest); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				break
+			}
+			b.WriteString("&amp;")
+			i++
+		case c == '>':
+			b.WriteString("&gt;")
+			i++
+		default:
+			j := i + 1
+			for j < len(text) && strings.IndexByte("\\\n`*_![<&>", text[j]) < 0 {
+				j++
+			}
+			b.WriteString(text[i:j])
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// renderEmphasis renders emphasis or strong emphasis starting with the delimiter run at text[i]
+// and returns the number of consumed bytes. Runs of one delimiter denote emphasis,
+// runs of two strong emphasis and runs of three both. Closing runs must be of the same length.
+func renderEmphasis(b *strings.Builder, text string, i int) int {
+	c := text[i]
+	n := delimiterRun(text, i)
+	start := i + n
+	opens := start < len(text) && text[start] != ' ' && text[start] != '\n' &&
+		(c == '*' || i == 0 || isWordChar(text[i-1]) == false) // no intraword emphasis with underscores
+	for j := start; opens && j < len(text); {
+		if text[j] == '\\' {
+			j += 2
+			continue
+		}
+		if text[j] != c {
+			j++
+			continue
+		}
+		m := delimiterRun(text, j)
+		after := j + m
+		closes := text[j-1] != ' ' && text[j-1] != '\n' && (c == '*' || after >= len(text) || isWordChar(text[after]) == false)
+		if closes && j > start && (m == n || n >= 3 && m >= 3) {
+			inner := renderInline(text[start:j])
+			switch {
+			case n == 1:
+				b.WriteString("<em>" + inner + "</em>")
+			case n == 2:
+				b.WriteString("<strong>" + inner + "</strong>")
+			default:
+				b.WriteString("<strong><em>" + inner + "</em></strong>")
+			}
+			return after - i
+		}
+		j = after // skip nested delimiter runs
+	}
+	b.WriteString(text[i:start])
+	return n
+}
+
+// delimiterRun returns the length of the run of equal characters at text[i].
+func delimiterRun(text string, i int) int {
+	n := 1
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func linkDestination(destination string) string {
+	destination = strings.TrimSuffix(strings.TrimPrefix(destination, "<"), ">")
+	return html.UnescapeString(destination)
+}
+
+var markup = regexp.MustCompile(`<[^>]*>`)
+
+// plainText returns the text of inline markdown, e.g. for the alternative text of images.
+func plainText(text string) string {
+	return html.UnescapeString(markup.ReplaceAllString(renderInline(text), ""))
+}
@@ -0,0 +1,40 @@
+package source
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"paragraphs", "Hello\nWorld\n\nBye", "<p>Hello\nWorld</p>\n<p>Bye</p>\n"},
+		{"atx headings", "# One\n### Three ###", "<h1>One</h1>\n<h3>Three</h3>\n"},
+		{"setext headings", "One\n===\nTwo\n---", "<h1>One</h1>\n<h2>Two</h2>\n"},
+		{"emphasis", "*em* _em_ **strong** __strong__ snake_case_name", "<p><em>em</em> <em>em</em> <strong>strong</strong> <strong>strong</strong> snake_case_name</p>\n"},
+		{"nested emphasis", "***both*** and *a **b** c*", "<p><strong><em>both</em></strong> and <em>a <strong>b</strong> c</em></p>\n"},
+		{"code span", "use `a < b` and ``x ` y``", "<p>use <code>a &lt; b</code> and <code>x ` y</code></p>\n"},
+		{"escaping", `AT&T 1 < 2 &amp; \*not em\*`, "<p>AT&amp;T 1 &lt; 2 &amp; *not em*</p>\n"},
+		{"hard break", "one  \ntwo\\\nthree", "<p>one<br>\ntwo<br>\nthree</p>\n"},
+		{"link", `[the *Go* blog](https://blog.golang.org "Go Blog")`, `<p><a href="https://blog.golang.org" title="Go Blog">the <em>Go</em> blog</a></p>` + "\n"},
+		{"image", "![a *gopher*](/gopher.png)", `<p><img src="/gopher.png" alt="a gopher"></p>` + "\n"},
+		{"autolinks", "<https://golang.org> <gopher@golang.org>", `<p><a href="https://golang.org">https://golang.org</a> <a href="mailto:gopher@golang.org">gopher@golang.org</a></p>` + "\n"},
+		{"raw html", `a <span class="x">b</span>`, `<p>a <span class="x">b</span></p>` + "\n"},
+		{"html block", "<div>\n*raw*\n</div>\n\ntext", "<div>\n*raw*\n</div>\n<p>text</p>\n"},
+		{"thematic break", "a\n\n* * *\n\nb", "<p>a</p>\n<hr>\n<p>b</p>\n"},
+		{"fenced code", "```go\nif a < b {\n}\n```", "<pre><code class=\"language-go\">if a &lt; b {\n}\n</code></pre>\n"},
+		{"indented code", "    x := 1\n\n    y := 2\n\nz", "<pre><code>x := 1\n\ny := 2\n</code></pre>\n<p>z</p>\n"},
+		{"block quote", "> quote\nlazy\n>\n> - item", "<blockquote>\n<p>quote\nlazy</p>\n<ul>\n<li>item</li>\n</ul>\n</blockquote>\n"},
+		{"tight list", "- one\n- two\n  - nested\n- three", "<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n<li>three</li>\n</ul>\n"},
+		{"loose list", "1. one\n\n2. two", "<ol>\n<li><p>one</p></li>\n<li><p>two</p></li>\n</ol>\n"},
+		{"ordered start", "3) three\n4) four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n"},
+		{"list after paragraph", "text\n- item\n\nnext", "<p>text</p>\n<ul>\n<li>item</li>\n</ul>\n<p>next</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Markdown([]byte(tt.markdown))); got != tt.want {
+				t.Errorf("Markdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
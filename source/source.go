@@ -0,0 +1,161 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+// Config configures the creation of entries from posts.
+type Config struct {
+	// FeedID is the ID of the feed, from which the entry IDs are derived (see Post.Entry).
+	FeedID atomfeed.ID
+	// BaseURL is prepended to the slash separated path of a post without extension to form its permalink,
+	// e.g. "https://example.com/posts/" and "2017/hello.md" result in "https://example.com/posts/2017/hello/".
+	BaseURL string
+	// Permalink overrides the permalink of a post based on its path relative to the walked directory.
+	Permalink func(path string) string
+	// Author is the default author of posts without author in their front matter.
+	Author *atomfeed.Person
+}
+
+// Post is a Markdown post with front matter.
+type Post struct {
+	Path        string // slash separated path relative to the walked directory
+	FrontMatter FrontMatter
+	HTML        []byte // rendered Markdown body
+}
+
+// Extensions lists the file extensions of Markdown posts.
+var Extensions = []string{".md", ".markdown"}
+
+// HTMLExtensions lists the file extensions of HTML posts, whose body is used without rendering.
+var HTMLExtensions = []string{".html", ".htm"}
+
+// Walk reads all Markdown and HTML posts in dir and its subdirectories and returns their entries,
+// sorted by date, newest first. Drafts are skipped.
+// Walk fails, if several posts share the same entry ID, e.g. posts of the same date and time
+// without an id in their front matter.
+func Walk(dir string, c Config) ([]atomfeed.Entry, error) {
+	posts, err := ReadPosts(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := []atomfeed.Entry{}
+	paths := map[string]string{} // entry ID → path of the post
+	for _, p := range posts {
+		if p.FrontMatter.Draft {
+			continue
+		}
+		e := p.Entry(c)
+		if other, ok := paths[e.ID.Value]; ok {
+			return nil, fmt.Errorf("%v: duplicate entry ID %v of %v: set a unique id in the front matter", p.Path, e.ID.Value, other)
+		}
+		paths[e.ID.Value] = p.Path
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ReadPosts reads and renders all Markdown and HTML posts in dir and its subdirectories, sorted by date, newest first.
+func ReadPosts(dir string) ([]Post, error) {
+	posts := []Post{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || isPost(file) == false {
+			return err
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		p, err := ParsePost(filepath.ToSlash(rel), data)
+		if err != nil {
+			return err
+		}
+		posts = append(posts, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].FrontMatter.Date.After(posts[j].FrontMatter.Date)
+	})
+	return posts, nil
+}
+
+// ParsePost parses the front matter of a post and renders its Markdown body.
+// The body of HTML posts (see HTMLExtensions) is used as is.
+// Title and date are mandatory.
+func ParsePost(path string, data []byte) (Post, error) {
+	fm, body, err := ParseFrontMatter(data)
+	if err != nil {
+		return Post{}, fmt.Errorf("%v: %v", path, err)
+	}
+	if fm.Title == "" {
+		return Post{}, fmt.Errorf("%v: front matter: missing title", path)
+	}
+	if fm.Date.IsZero() {
+		return Post{}, fmt.Errorf("%v: front matter: missing date", path)
+	}
+	if hasExtension(path, HTMLExtensions) {
+		return Post{Path: path, FrontMatter: fm, HTML: bytes.TrimSpace(body)}, nil
+	}
+	return Post{Path: path, FrontMatter: fm, HTML: Markdown(body)}, nil
+}
+
+// Entry creates an atom entry of the post.
+// The ID is taken from the front matter or derived from the post's date with atomfeed.NewEntryID,
+// so that it doesn't change when the post is renamed or moved.
+func (p *Post) Entry(c Config) atomfeed.Entry {
+	fm := p.FrontMatter
+	id := atomfeed.NewEntryID(c.FeedID, fm.Date)
+	if fm.ID != "" {
+		id = atomfeed.NewID(fm.ID)
+	}
+	updated := fm.Updated
+	if updated.IsZero() {
+		updated = fm.Date
+	}
+	author := c.Author
+	if fm.Author != "" {
+		author = atomfeed.NewPerson(fm.Author, "", "")
+	}
+	var summary []byte
+	if fm.Summary != "" {
+		summary = []byte(html.EscapeString(fm.Summary))
+	}
+	permalink := ""
+	if c.Permalink != nil {
+		permalink = c.Permalink(p.Path)
+	} else {
+		permalink = strings.TrimSuffix(c.BaseURL, "/") + "/" + strings.TrimSuffix(p.Path, path.Ext(p.Path)) + "/"
+	}
+	return atomfeed.NewEntry(id, fm.Title, permalink, author, updated, fm.Date, fm.Tags, summary, p.HTML)
+}
+
+func isPost(file string) bool {
+	return hasExtension(file, Extensions) || hasExtension(file, HTMLExtensions)
+}
+
+func hasExtension(file string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denisbrodbeck/atomfeed"
+)
+
+func TestWalk(t *testing.T) {
+	gopher := atomfeed.NewPerson("Go Pher", "", "")
+	entries, err := Walk("testdata/posts", Config{
+		FeedID:  atomfeed.NewID("tag:example.com,2005-12-21:blog"),
+		BaseURL: "https://example.com/posts",
+		Author:  gopher,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Walk() returned %d entries, want 2", len(entries))
+	}
+	release, hello := entries[0], entries[1]
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"id from front matter", release.ID.Value, "tag:example.com,2005-12-21:blog.release"},
+		{"derived id", hello.ID.Value, "tag:example.com,2005-12-21:blog.post-20171221083015"},
+		{"permalink", release.Links[0].Href, "https://example.com/posts/2017/release/"},
+		{"updated", release.Updated.Time(), time.Date(2017, time.December, 23, 8, 30, 15, 0, time.UTC)},
+		{"published", release.Published.Time(), time.Date(2017, time.December, 22, 8, 30, 15, 0, time.UTC)},
+		{"updated defaults to date", hello.Updated.Time(), time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)},
+		{"author from front matter", release.Author.Name, "Octo Cat"},
+		{"default author", hello.Author, gopher},
+		{"categories", len(hello.Categories), 2},
+		{"summary", hello.Summary.Value, "Our very first post."},
+		{"no summary", release.Summary == nil, true},
+		{"content", hello.Content.Value, "<h1>Hello <em>World</em></h1>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Walk() %v = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+	feed := atomfeed.NewFeed(atomfeed.NewID("tag:example.com,2005-12-21:blog"), gopher, "Blog", "", "https://example.com/", "https://example.com/feed.atom", time.Now(), entries)
+	if err := feed.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParsePost(t *testing.T) {
+	if _, err := ParsePost("untitled.md", []byte("---\ndate: 2017-12-21\n---\n")); err == nil {
+		t.Error("ParsePost() should fail on missing title, did not")
+	}
+	if _, err := ParsePost("undated.md", []byte("---\ntitle: Hello\n---\n")); err == nil {
+		t.Error("ParsePost() should fail on missing date, did not")
+	}
+}
+
+func TestParsePostHTML(t *testing.T) {
+	p, err := ParsePost("hello.html", []byte("---\ntitle: Hello\ndate: 2017-12-21\n---\n<p>*not markdown*</p>\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(p.HTML); got != "<p>*not markdown*</p>" {
+		t.Errorf("ParsePost() html = %q, want body as is", got)
+	}
+}
+
+func TestWalkSameDate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, frontMatter string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("---\ntitle: Post\ndate: 2017-12-21\n"+frontMatter+"---\nText\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("one.md", "")
+	write("two.md", "")
+	c := Config{FeedID: atomfeed.NewID("tag:example.com,2005-12-21:blog")}
+	if _, err := Walk(dir, c); err == nil || strings.Contains(err.Error(), "duplicate entry ID tag:example.com,2005-12-21:blog.post-20171221000000") == false {
+		t.Errorf("Walk() error = %v, want duplicate entry ID of posts of the same date", err)
+	}
+
+	write("two.md", "id: tag:example.com,2005-12-21:blog.two\n")
+	entries, err := Walk(dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID.Value == entries[1].ID.Value {
+		t.Errorf("Walk() returned %d entries with IDs %v, want 2 distinct IDs", len(entries), entries)
+	}
+
+	write("one.md", "id: tag:example.com,2005-12-21:blog.post\n")
+	write("two.md", "id: tag:example.com,2005-12-21:blog.post\n")
+	if _, err := Walk(dir, c); err == nil {
+		t.Error("Walk() should fail on duplicate entry IDs, did not")
+	}
+}
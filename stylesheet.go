@@ -0,0 +1,170 @@
+package atomfeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Stylesheet is an xml-stylesheet processing instruction, which links a stylesheet
+// rendering the feed as a readable page when it is opened in a browser.
+//  https://www.w3.org/TR/xml-stylesheet/
+type Stylesheet struct {
+	Href string
+	Type string // media type of the stylesheet, defaults to "text/xsl"
+}
+
+// NewStylesheet creates an xml-stylesheet processing instruction for the XSL stylesheet at href.
+// Browsers apply stylesheets only if they are served from the same origin as the feed.
+func NewStylesheet(href string) *Stylesheet {
+	return &Stylesheet{Href: href, Type: "text/xsl"}
+}
+
+// procInst returns the processing instruction including the trailing newline.
+func (s *Stylesheet) procInst() []byte {
+	contentType := s.Type
+	if contentType == "" {
+		contentType = "text/xsl"
+	}
+	b := &bytes.Buffer{}
+	b.WriteString(`<?xml-stylesheet type="`)
+	xml.EscapeText(b, []byte(contentType))
+	b.WriteString(`" href="`)
+	xml.EscapeText(b, []byte(s.Href))
+	b.WriteString("\"?>\n")
+	return b.Bytes()
+}
+
+var pseudoAttribute = regexp.MustCompile(`([\w-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// parseStylesheet parses the pseudo-attributes of an xml-stylesheet processing instruction.
+func parseStylesheet(inst []byte) *Stylesheet {
+	s := &Stylesheet{}
+	for _, m := range pseudoAttribute.FindAllSubmatch(inst, -1) {
+		value := html.UnescapeString(string(m[2]) + string(m[3]))
+		switch string(m[1]) {
+		case "href":
+			s.Href = value
+		case "type":
+			s.Type = value
+		}
+	}
+	return s
+}
+
+// stylesheetReader is a xml.TokenReader, which stores the xml-stylesheet processing instruction
+// preceding the root element in feed.
+type stylesheetReader struct {
+	r    xml.TokenReader
+	feed *Feed
+	root bool // whether the root element was read
+}
+
+func (r *stylesheetReader) Token() (xml.Token, error) {
+	t, err := r.r.Token()
+	switch t := t.(type) {
+	case xml.ProcInst:
+		if r.root == false && t.Target == "xml-stylesheet" && r.feed.Stylesheet == nil {
+			r.feed.Stylesheet = parseStylesheet(t.Inst)
+		}
+	case xml.StartElement:
+		r.root = true
+	}
+	return t, err
+}
+
+// StylesheetHandler serves DefaultXSL, e.g. at the href of NewStylesheet.
+func StylesheetHandler() http.Handler {
+	modified := time.Now()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xsl; charset=utf-8")
+		http.ServeContent(w, r, "feed.xsl", modified, strings.NewReader(DefaultXSL))
+	})
+}
+
+// DefaultXSL is an XSL stylesheet, which renders a feed and its entries as a readable page in browsers.
+// Html content is rendered by browsers supporting disable-output-escaping and shown as text by others.
+const DefaultXSL = `<?xml version="1.0" encoding="UTF-8"?>
+<xsl:stylesheet version="1.0"
+  xmlns:xsl="http://www.w3.org/1999/XSL/Transform"
+  xmlns:atom="http://www.w3.org/2005/Atom"
+  xmlns:xhtml="http://www.w3.org/1999/xhtml"
+  exclude-result-prefixes="atom xhtml">
+  <xsl:output method="html" encoding="UTF-8" indent="yes"/>
+
+  <xsl:template match="/atom:feed">
+    <html>
+      <head>
+        <meta charset="utf-8"/>
+        <meta name="viewport" content="width=device-width, initial-scale=1"/>
+        <title><xsl:value-of select="atom:title"/></title>
+        <style>
+          body { font-family: sans-serif; max-width: 46em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+          .notice { background: #fdf6e3; border: 1px solid #e8d9a8; padding: 0.5em 1em; }
+          .meta { color: #666; }
+          article { border-top: 1px solid #ddd; padding: 1em 0; }
+          img { max-width: 100%; }
+        </style>
+      </head>
+      <body>
+        <p class="notice">This is a web feed. Copy its address into your feed reader to subscribe.</p>
+        <header>
+          <h1>
+            <a href="{atom:link[@rel='alternate' or not(@rel)][1]/@href}"><xsl:apply-templates select="atom:title"/></a>
+          </h1>
+          <xsl:if test="atom:subtitle">
+            <p><xsl:apply-templates select="atom:subtitle"/></p>
+          </xsl:if>
+        </header>
+        <main>
+          <xsl:apply-templates select="atom:entry"/>
+        </main>
+      </body>
+    </html>
+  </xsl:template>
+
+  <xsl:template match="atom:entry">
+    <article>
+      <h2>
+        <a href="{atom:link[@rel='alternate' or not(@rel)][1]/@href}"><xsl:apply-templates select="atom:title"/></a>
+      </h2>
+      <p class="meta">
+        <xsl:value-of select="substring(atom:updated, 1, 10)"/>
+        <xsl:if test="atom:author/atom:name">
+          <xsl:text> · </xsl:text>
+          <xsl:value-of select="atom:author/atom:name"/>
+        </xsl:if>
+      </p>
+      <xsl:choose>
+        <xsl:when test="atom:summary">
+          <xsl:apply-templates select="atom:summary"/>
+        </xsl:when>
+        <xsl:otherwise>
+          <xsl:apply-templates select="atom:content"/>
+        </xsl:otherwise>
+      </xsl:choose>
+    </article>
+  </xsl:template>
+
+  <xsl:template match="atom:title | atom:subtitle | atom:summary | atom:content">
+    <xsl:choose>
+      <xsl:when test="@type = 'html'">
+        <xsl:value-of select="." disable-output-escaping="yes"/>
+      </xsl:when>
+      <xsl:when test="@type = 'xhtml'">
+        <xsl:copy-of select="xhtml:div/node()"/>
+      </xsl:when>
+      <xsl:when test="@src">
+        <a href="{@src}"><xsl:value-of select="@src"/></a>
+      </xsl:when>
+      <xsl:otherwise>
+        <xsl:value-of select="."/>
+      </xsl:otherwise>
+    </xsl:choose>
+  </xsl:template>
+</xsl:stylesheet>
+`
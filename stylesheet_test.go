@@ -0,0 +1,67 @@
+package atomfeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStylesheetEncode(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feed := NewFeed(NewFeedID("example.com", now, "blog"), NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, nil)
+	feed.Stylesheet = NewStylesheet("/feed.xsl?v=1&theme=dark")
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	want := xml.Header + `<?xml-stylesheet type="text/xsl" href="/feed.xsl?v=1&amp;theme=dark"?>` + "\n<feed "
+	if strings.HasPrefix(out.String(), want) == false {
+		t.Errorf("Encode() = %v, want prefix %v", out, want)
+	}
+
+	got, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Stylesheet, feed.Stylesheet) {
+		t.Errorf("Decode() stylesheet = %+v, want %+v", got.Stylesheet, feed.Stylesheet)
+	}
+	got, err = Decode(strings.NewReader(`<?xml-stylesheet href='a.css' type='text/css'?><feed xmlns="http://www.w3.org/2005/Atom"><?xml-stylesheet href="b.xsl"?></feed>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&Stylesheet{Href: "a.css", Type: "text/css"}); !reflect.DeepEqual(got.Stylesheet, want) {
+		t.Errorf("Decode() stylesheet = %+v, want %+v", got.Stylesheet, want)
+	}
+}
+
+func TestStylesheetHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	StylesheetHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/feed.xsl", nil))
+	if got := rec.Header().Get("Content-Type"); got != "text/xsl; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/xsl", got)
+	}
+	body, _ := ioutil.ReadAll(rec.Body)
+	if string(body) != DefaultXSL {
+		t.Error("StylesheetHandler() did not serve DefaultXSL")
+	}
+}
+
+func TestDefaultXSLWellFormed(t *testing.T) {
+	d := xml.NewDecoder(strings.NewReader(DefaultXSL))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DefaultXSL is not well-formed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package atomfeed
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SubFeedConfig configures the generation of sub-feeds (see CategoryFeeds and AuthorFeeds).
+type SubFeedConfig struct {
+	// Specifier is prepended to the slug of the category term or author name
+	// and appended to the ID of the master feed, e.g. "tag" derives the ID
+	// "tag:example.com,2005:blog.tag-go" from "tag:example.com,2005:blog".
+	Specifier string
+	// SelfURL is the URL template of the sub-feed itself, e.g. "https://example.com/tags/{slug}/feed.atom".
+	SelfURL string
+	// AlternateURL is the URL template of the HTML page of the category or author, e.g. "https://example.com/tags/{slug}/".
+	AlternateURL string
+}
+
+// SubFeed is a feed derived from a master feed, which contains the entries of a single category or author.
+type SubFeed struct {
+	Name string // category term or author name
+	Slug string // URL and ID friendly form of the name
+	Feed Feed
+}
+
+// CategoryFeeds returns one sub-feed per category term of the feed's entries, sorted by slug.
+// Each sub-feed keeps the metadata of the master feed, but gets a derived ID and title,
+// and the self and alternate links built from the URL templates of the config.
+// Terms differing only in case, whitespace, dashes or underscores (like "Go" and "go") share a sub-feed.
+// Terms with characters other than letters and digits (like "C#" and "C++") get a slug with a suffix,
+// which keeps them apart from similar terms (like "C"), see subFeedSlugs.
+func (f *Feed) CategoryFeeds(c SubFeedConfig) []SubFeed {
+	names := []string{}
+	for _, e := range f.Entries {
+		for _, category := range e.Categories {
+			names = append(names, category.Term)
+		}
+	}
+	return f.subFeeds(c, names, func(e *Entry, key string) bool {
+		for _, category := range e.Categories {
+			if nameKey(category.Term) == key {
+				return true
+			}
+		}
+		return false
+	}, nil)
+}
+
+// AuthorFeeds returns one sub-feed per author name of the feed's entries, sorted by slug (see CategoryFeeds).
+// Entries without author are attributed to the author of the feed.
+// The author of each sub-feed is set to its author.
+func (f *Feed) AuthorFeeds(c SubFeedConfig) []SubFeed {
+	author := func(e *Entry) *Person {
+		if e.Author != nil {
+			return e.Author
+		}
+		return f.Author
+	}
+	names := []string{}
+	authors := map[string]*Person{}
+	for i := range f.Entries {
+		if a := author(&f.Entries[i]); a != nil {
+			names = append(names, a.Name)
+			if authors[nameKey(a.Name)] == nil {
+				authors[nameKey(a.Name)] = a
+			}
+		}
+	}
+	return f.subFeeds(c, names, func(e *Entry, key string) bool {
+		a := author(e)
+		return a != nil && nameKey(a.Name) == key
+	}, authors)
+}
+
+// subFeeds creates a sub-feed per distinct name key of names.
+func (f *Feed) subFeeds(c SubFeedConfig, names []string, match func(e *Entry, key string) bool, authors map[string]*Person) []SubFeed {
+	subFeeds := []SubFeed{}
+	slugs := subFeedSlugs(names)
+	done := map[string]bool{}
+	for _, name := range names {
+		key := nameKey(name)
+		s := slugs[key]
+		if s == "" || done[key] {
+			continue
+		}
+		done[key] = true
+		sub := f.Filter(func(e Entry) bool { return match(&e, key) })
+		sub.ID = NewID(f.ID.Value + "." + strings.Trim(c.Specifier+"-"+s, "-"))
+		sub.Title = &TextConstruct{Value: strings.TrimSpace(textValue(f.Title) + " – " + name)}
+		sub.Links = subFeedLinks(f.Links, expandSlug(c.AlternateURL, s), expandSlug(c.SelfURL, s))
+		if author, ok := authors[key]; ok {
+			sub.Author = author
+		}
+		subFeeds = append(subFeeds, SubFeed{Name: name, Slug: s, Feed: *sub})
+	}
+	sort.Slice(subFeeds, func(i, j int) bool { return subFeeds[i].Slug < subFeeds[j].Slug })
+	return subFeeds
+}
+
+// subFeedLinks replaces the links of the master feed, which do not apply to a sub-feed.
+func subFeedLinks(links []Link, alternate, self string) []Link {
+	sub := []Link{}
+	if alternate != "" {
+		sub = append(sub, Link{Rel: "alternate", Type: "text/html", Href: alternate})
+	}
+	if self != "" {
+		sub = append(sub, Link{Rel: "self", Type: "application/atom+xml", Href: self})
+	}
+	for _, l := range links {
+		switch l.Rel {
+		case "", "alternate", "self", "first", "previous", "next", "last":
+			continue
+		}
+		sub = append(sub, l)
+	}
+	return sub
+}
+
+// expandSlug replaces the {slug} parameter of the URL template.
+func expandSlug(template, slug string) string {
+	return strings.Replace(template, "{slug}", url.PathEscape(slug), -1)
+}
+
+// nameKey identifies the names sharing a sub-feed: names are compared case-insensitively
+// and runs of whitespace, dashes and underscores are equal.
+func nameKey(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	}), "-")
+}
+
+// subFeedSlugs returns the slugs of the name keys of names.
+// A name key, which differs from its slug (like "c++" or "c#"), gets a suffix derived from the key,
+// so that distinct names never share a slug. The slug only depends on the name itself,
+// so slugs (and the IDs of sub-feeds) remain stable when other names are added.
+func subFeedSlugs(names []string) map[string]string {
+	slugs := map[string]string{}
+	for _, name := range names {
+		key := nameKey(name)
+		s := slug(key)
+		if s == "" {
+			continue
+		}
+		if key != s {
+			h := fnv.New32a()
+			h.Write([]byte(key))
+			s = fmt.Sprintf("%s-%08x", s, h.Sum32())
+		}
+		slugs[key] = s
+	}
+	return slugs
+}
+
+// slug returns a lowercase form of name, which only consists of letters, digits and dashes.
+func slug(name string) string {
+	b := &strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
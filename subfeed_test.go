@@ -0,0 +1,122 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFeedCategoryFeeds(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feed := NewFeed(NewID("tag:example.com,2005:blog"), NewPerson("Go Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, []Entry{
+		{ID: NewID("1"), Title: &TextConstruct{Value: "One"}, Updated: NewDate(now.Add(-2 * time.Hour)), Categories: []Category{*NewCategory("Go"), *NewCategory("Web Dev")}},
+		{ID: NewID("2"), Title: &TextConstruct{Value: "Two"}, Updated: NewDate(now.Add(-time.Hour)), Categories: []Category{*NewCategory("go")}},
+	})
+	feed.Links = append(feed.Links, NewLicense("https://creativecommons.org/licenses/by/4.0/"))
+	subFeeds := feed.CategoryFeeds(SubFeedConfig{
+		Specifier:    "tag",
+		SelfURL:      "https://example.com/tags/{slug}/feed.atom",
+		AlternateURL: "https://example.com/tags/{slug}/",
+	})
+	if len(subFeeds) != 2 {
+		t.Fatalf("CategoryFeeds() returned %d feeds, want 2", len(subFeeds))
+	}
+	goFeed, webFeed := subFeeds[0].Feed, subFeeds[1].Feed
+	if subFeeds[0].Slug != "go" || subFeeds[1].Slug != "web-dev" {
+		t.Errorf("CategoryFeeds() slugs = %v, %v, want go, web-dev", subFeeds[0].Slug, subFeeds[1].Slug)
+	}
+	if goFeed.ID.Value != "tag:example.com,2005:blog.tag-go" {
+		t.Errorf("CategoryFeeds() id = %v", goFeed.ID.Value)
+	}
+	if len(goFeed.Entries) != 2 || len(webFeed.Entries) != 1 {
+		t.Errorf("CategoryFeeds() returned %d and %d entries, want 2 and 1", len(goFeed.Entries), len(webFeed.Entries))
+	}
+	if webFeed.Updated.Time() != now.Add(-2*time.Hour) {
+		t.Errorf("CategoryFeeds() updated = %v, want date of remaining entry", webFeed.Updated)
+	}
+	wantLinks := []Link{
+		{Rel: "alternate", Type: "text/html", Href: "https://example.com/tags/web-dev/"},
+		{Rel: "self", Type: "application/atom+xml", Href: "https://example.com/tags/web-dev/feed.atom"},
+		NewLicense("https://creativecommons.org/licenses/by/4.0/"),
+	}
+	if !reflect.DeepEqual(webFeed.Links, wantLinks) {
+		t.Errorf("CategoryFeeds() links = %v, want %v", webFeed.Links, wantLinks)
+	}
+	if err := webFeed.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFeedAuthorFeeds(t *testing.T) {
+	gopher := NewPerson("Go Pher", "", "")
+	octocat := NewPerson("Octo Cat", "", "")
+	feed := Feed{
+		ID:     NewID("tag:example.com,2005:blog"),
+		Author: gopher,
+		Entries: []Entry{
+			{ID: NewID("1")},
+			{ID: NewID("2"), Author: octocat},
+			{ID: NewID("3"), Author: gopher},
+		},
+	}
+	subFeeds := feed.AuthorFeeds(SubFeedConfig{Specifier: "author", SelfURL: "/authors/{slug}.atom"})
+	if len(subFeeds) != 2 {
+		t.Fatalf("AuthorFeeds() returned %d feeds, want 2", len(subFeeds))
+	}
+	gopherFeed, octocatFeed := subFeeds[0].Feed, subFeeds[1].Feed
+	if gopherFeed.ID.Value != "tag:example.com,2005:blog.author-go-pher" || gopherFeed.Links[0].Href != "/authors/go-pher.atom" {
+		t.Errorf("AuthorFeeds() id = %v, links = %v", gopherFeed.ID.Value, gopherFeed.Links)
+	}
+	if len(gopherFeed.Entries) != 2 || len(octocatFeed.Entries) != 1 || octocatFeed.Author != octocat {
+		t.Errorf("AuthorFeeds() = %v, %v", gopherFeed.Entries, octocatFeed.Entries)
+	}
+}
+
+func Test_slug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"go", "go"},
+		{"Web Dev", "web-dev"},
+		{"  C++ / Go!  ", "c-go"},
+		{"Jürgen Müller", "jürgen-müller"},
+		{"+++", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slug(tt.name); got != tt.want {
+				t.Errorf("slug() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedCategoryFeedsCollisions(t *testing.T) {
+	feed := Feed{ID: NewID("tag:example.com,2005:blog"), Entries: []Entry{
+		{ID: NewID("1"), Categories: []Category{*NewCategory("C"), *NewCategory("C++")}},
+		{ID: NewID("2"), Categories: []Category{*NewCategory("C#"), *NewCategory("c++")}},
+	}}
+	subFeeds := feed.CategoryFeeds(SubFeedConfig{Specifier: "tag"})
+	got := map[string]int{}
+	slugs := map[string]bool{}
+	for _, sub := range subFeeds {
+		got[sub.Name] = len(sub.Feed.Entries)
+		slugs[sub.Slug] = true
+	}
+	if want := map[string]int{"C": 1, "C++": 2, "C#": 1}; reflect.DeepEqual(got, want) == false || len(slugs) != 3 || slugs["c"] == false {
+		t.Errorf("CategoryFeeds() = %v with slugs %v, want %v with distinct slugs", got, slugs, want)
+	}
+
+	// the slug of a name doesn't depend on the other names
+	before := Feed{ID: feed.ID, Entries: []Entry{{ID: NewID("1"), Categories: []Category{*NewCategory("C++")}}}}
+	if subs := before.CategoryFeeds(SubFeedConfig{Specifier: "tag"}); len(subs) != 1 || slugs[subs[0].Slug] == false || subs[0].Slug == "c" {
+		t.Errorf("CategoryFeeds() of C++ alone = %+v, want the slug of C++ next to C and C#", subs)
+	}
+	alone := Feed{ID: feed.ID, Entries: []Entry{{ID: NewID("1"), Categories: []Category{*NewCategory("C#")}}, {ID: NewID("2"), Categories: []Category{*NewCategory("C++")}}}}
+	for _, sub := range alone.CategoryFeeds(SubFeedConfig{}) {
+		if slugs[sub.Slug] == false {
+			t.Errorf("CategoryFeeds() slug of %v = %v, want the same slug as with other names", sub.Name, sub.Slug)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"strings"
+	"unicode"
+)
+
+// DefaultEllipsis is appended to summaries, which had to be truncated.
+const DefaultEllipsis = "…"
+
+// NewSummary returns an atom:summary element of type "text" with an excerpt of content.
+// Markup of html and xhtml content is removed, entities are decoded and
+// whitespace is collapsed, while block elements keep their words apart.
+// The excerpt is truncated to at most length characters (including the ellipsis) on a
+// sentence or word boundary. A length of zero or less disables truncation.
+// NewSummary returns nil for content without text, like base64 encoded or out-of-line content.
+func NewSummary(content *Content, length int, ellipsis string) *Content {
+	text := contentText(content)
+	if text == "" {
+		return nil
+	}
+	return &Content{Type: "text", Value: truncateText(text, length, ellipsis)}
+}
+
+// Summarize fills a missing summary of the entry with an excerpt of its content (see NewSummary).
+// Entries with content lacking text (base64 encoded or out-of-line content) are summarized with their title.
+// An existing summary is left untouched.
+func (e *Entry) Summarize(length int, ellipsis string) {
+	if e.Summary != nil {
+		return
+	}
+	if e.Summary = NewSummary(e.Content, length, ellipsis); e.Summary != nil {
+		return
+	}
+	if e.Content == nil {
+		return // no summary required
+	}
+	if title := collapseSpace(textValue(e.Title)); title != "" {
+		e.Summary = &Content{Type: "text", Value: truncateText(title, length, ellipsis)}
+	}
+}
+
+// Summarize fills missing summaries of all entries of the feed (see Entry.Summarize).
+func (f *Feed) Summarize(length int, ellipsis string) {
+	for i := range f.Entries {
+		f.Entries[i].Summarize(length, ellipsis)
+	}
+}
+
+// contentText returns the plain text of inline textual content.
+func contentText(c *Content) string {
+	switch {
+	case c == nil, c.Source != "", c.base64Encoded:
+		return ""
+	case c.Type == "html":
+		return htmlToText(c.Value)
+	case c.Type == "xhtml":
+		return htmlToText(c.ValueXML)
+	case isTextContentType(c.Type):
+		return collapseSpace(c.Value)
+	}
+	return ""
+}
+
+// blockElements separate their words from the surrounding text.
+var blockElements = toSet([]string{
+	"address", "article", "aside", "blockquote", "br", "caption", "dd", "div", "dl", "dt",
+	"figcaption", "figure", "footer", "h1", "h2", "h3", "h4", "h5", "h6", "header",
+	"hr", "li", "main", "nav", "ol", "p", "pre", "section", "table", "td", "th", "tr", "ul",
+})
+
+// htmlToText returns the text of a (X)HTML fragment with collapsed whitespace.
+// Character data of script and style elements is dropped.
+func htmlToText(html string) string {
+	tokens := htmlTokens(html)
+	b := &strings.Builder{}
+	hidden := 0
+	for _, t := range tokens {
+		switch t := t.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if name == "script" || name == "style" {
+				hidden++
+			}
+			if blockElements[name] {
+				b.WriteByte(' ')
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			if (name == "script" || name == "style") && hidden > 0 {
+				hidden--
+			}
+			if blockElements[name] {
+				b.WriteByte(' ')
+			}
+		case xml.CharData:
+			if hidden == 0 {
+				b.Write(t)
+			}
+		}
+	}
+	return collapseSpace(b.String())
+}
+
+// collapseSpace replaces all runs of whitespace (including non-breaking spaces) with a single space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateText shortens text to at most length runes including the ellipsis.
+// The text is cut after the last complete sentence, if that keeps at least half of the text,
+// or else after the last complete word. Complete sentences are not followed by an ellipsis.
+func truncateText(text string, length int, ellipsis string) string {
+	runes := []rune(text)
+	if length <= 0 || len(runes) <= length {
+		return text
+	}
+	limit := length - len([]rune(ellipsis))
+	if limit <= 0 {
+		return string([]rune(ellipsis)[:length])
+	}
+	// sentence boundary: the end of a sentence must fit into length without an ellipsis
+	for i := length - 1; i >= length/2; i-- {
+		if strings.ContainsRune(".!?", runes[i]) && unicode.IsSpace(runes[i+1]) {
+			return string(runes[:i+1])
+		}
+	}
+	// word boundary
+	cut := limit
+	if unicode.IsSpace(runes[cut]) == false {
+		for cut > 0 && unicode.IsSpace(runes[cut-1]) == false {
+			cut--
+		}
+		if cut == 0 {
+			cut = limit // a single long word
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-–—", r)
+	}) + ellipsis
+}
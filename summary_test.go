@@ -0,0 +1,79 @@
+package atomfeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *Content
+		length  int
+		want    *Content
+	}{
+		{"nil", nil, 20, nil},
+		{"out-of-line", &Content{Source: "https://example.com/gopher.png", Type: "image/png"}, 20, nil},
+		{"base64", NewContent("image/gif", "", []byte("GIF87a")), 20, nil},
+		{"text", &Content{Value: "  Go   is fun  "}, 0, &Content{Type: "text", Value: "Go is fun"}},
+		{"html", &Content{Type: "html", Value: "<h1>Header</h1><p>Tom &amp; Jerry&nbsp;are<br>friends</p><script>alert(1)</script>"}, 0, &Content{Type: "text", Value: "Header Tom & Jerry are friends"}},
+		{"xhtml", &Content{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml"><p>One</p><p>Two</p></div>`}, 0, &Content{Type: "text", Value: "One Two"}},
+		{"word boundary", &Content{Type: "html", Value: "<p>The quick brown fox jumps over the lazy dog</p>"}, 20, &Content{Type: "text", Value: "The quick brown fox…"}},
+		{"malformed html", &Content{Type: "html", Value: "<p>1 < 2<p>Tom & Jerry<img src=a.png alt=(x)>"}, 0, &Content{Type: "text", Value: "1 < 2 Tom & Jerry"}},
+		{"sentence boundary", &Content{Type: "html", Value: "<p>Go is fun. It is fast too.</p>"}, 16, &Content{Type: "text", Value: "Go is fun."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewSummary(tt.content, tt.length, DefaultEllipsis); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSummary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_truncateText(t *testing.T) {
+	tests := []struct {
+		text     string
+		length   int
+		ellipsis string
+		want     string
+	}{
+		{"short", 10, "…", "short"},
+		{"exactly ten", 11, "…", "exactly ten"},
+		{"one two three", 9, "...", "one..."},
+		{"one, two three", 8, "…", "one…"},
+		{"supercalifragilistic", 6, "…", "super…"},
+		{"Short. Then a much longer sentence.", 12, "…", "Short. Then…"},
+		{"Größenwahn ist schön", 12, "…", "Größenwahn…"},
+		{"anything", 2, "...", ".."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := truncateText(tt.text, tt.length, tt.ellipsis); got != tt.want {
+				t.Errorf("truncateText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntrySummarize(t *testing.T) {
+	existing := &Content{Type: "text", Value: "existing"}
+	tests := []struct {
+		name  string
+		entry Entry
+		want  *Content
+	}{
+		{"keep summary", Entry{Summary: existing, Content: &Content{Value: "content"}}, existing},
+		{"from content", Entry{Content: &Content{Type: "html", Value: "<p>content</p>"}}, &Content{Type: "text", Value: "content"}},
+		{"from title", Entry{Title: &TextConstruct{Value: "Gopher"}, Content: &Content{Source: "https://example.com/gopher.png", Type: "image/png"}}, &Content{Type: "text", Value: "Gopher"}},
+		{"no content", Entry{Title: &TextConstruct{Value: "Gopher"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.entry.Summarize(100, DefaultEllipsis)
+			if !reflect.DeepEqual(tt.entry.Summary, tt.want) {
+				t.Errorf("Summarize() = %v, want %v", tt.entry.Summary, tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"net/mail"
 	"net/url"
 	"strings"
-	"time"
 )
 
 // VerificationError describes problems encountered during feed verification.
@@ -46,13 +45,34 @@ func (f *Feed) Verify() *VerificationError {
 			errors = append(errors, fmt.Errorf("feed: icon: %v", err))
 		}
 	}
-	if f.Title == nil || f.Title.Value == "" {
+	if err := checkGeo(f.Geo); err != nil {
+		errors = append(errors, fmt.Errorf("feed: %v", err))
+	}
+	if err := checkDublinCore(f.DublinCore); err != nil {
+		errors = append(errors, fmt.Errorf("feed: %v", err))
+	}
+	if err := checkLicenses(f.Links); err != nil {
+		errors = append(errors, fmt.Errorf("feed: %v", err))
+	}
+	if err := checkOpenSearch(f.OpenSearch); err != nil {
+		errors = append(errors, fmt.Errorf("feed: %v", err))
+	}
+	if isTextEmpty(f.Title) {
 		errors = append(errors, fmt.Errorf("feed: missing title"))
 	}
+	if err := checkText(f.Title); err != nil {
+		errors = append(errors, fmt.Errorf("feed: title: %v", err))
+	}
+	if err := checkText(f.Subtitle); err != nil {
+		errors = append(errors, fmt.Errorf("feed: subtitle: %v", err))
+	}
+	if err := checkText(f.Copyright); err != nil {
+		errors = append(errors, fmt.Errorf("feed: rights: %v", err))
+	}
 	if f.Updated == nil {
 		errors = append(errors, fmt.Errorf("feed: missing updated date"))
 	} else {
-		if err := checkDate(f.Updated.Value); err != nil {
+		if err := checkDate(f.Updated); err != nil {
 			errors = append(errors, fmt.Errorf("feed: updated: %v", err))
 		}
 	}
@@ -82,18 +102,41 @@ func (e *Entry) Verify() *VerificationError {
 	if err := checkPerson(e.Author); err != nil {
 		errors = append(errors, fmt.Errorf("entry: author: %v", err))
 	}
-	if e.Title == nil || e.Title.Value == "" {
+	if err := checkMedia(e.Media); err != nil {
+		errors = append(errors, fmt.Errorf("entry: %v", err))
+	}
+	if err := checkGeo(e.Geo); err != nil {
+		errors = append(errors, fmt.Errorf("entry: %v", err))
+	}
+	if err := checkDublinCore(e.DublinCore); err != nil {
+		errors = append(errors, fmt.Errorf("entry: %v", err))
+	}
+	if err := checkLicenses(e.Links); err != nil {
+		errors = append(errors, fmt.Errorf("entry: %v", err))
+	}
+	if e.Source != nil {
+		if err := checkLicenses(e.Source.Links); err != nil {
+			errors = append(errors, fmt.Errorf("entry: source: %v", err))
+		}
+	}
+	if isTextEmpty(e.Title) {
 		errors = append(errors, fmt.Errorf("entry: missing title"))
 	}
+	if err := checkText(e.Title); err != nil {
+		errors = append(errors, fmt.Errorf("entry: title: %v", err))
+	}
+	if err := checkText(e.Copyright); err != nil {
+		errors = append(errors, fmt.Errorf("entry: rights: %v", err))
+	}
 	if e.Updated == nil {
 		errors = append(errors, fmt.Errorf("entry: missing updated date"))
 	} else {
-		if err := checkDate(e.Updated.Value); err != nil {
+		if err := checkDate(e.Updated); err != nil {
 			errors = append(errors, fmt.Errorf("entry: updated: %v", err))
 		}
 	}
 	if e.Published != nil {
-		if err := checkDate(e.Published.Value); err != nil {
+		if err := checkDate(e.Published); err != nil {
 			errors = append(errors, fmt.Errorf("entry: published: %v", err))
 		}
 	}
@@ -174,13 +217,37 @@ func checkID(id ID) error {
 	return checkURI(id.Value)
 }
 
-func checkDate(date string) error {
-	t, err := time.Parse(time.RFC3339, date)
-	if err != nil {
-		return fmt.Errorf("invalid date: %v", err)
+func checkDate(date *Date) error {
+	if date.Time().IsZero() && date != nil && date.Value != "" {
+		_, err := ParseDate(date.Value)
+		return err
 	}
-	if t.IsZero() {
-		return fmt.Errorf("invalid date %q: date is zero", date)
+	if date.Time().IsZero() {
+		return fmt.Errorf("invalid date %q: date is zero", date.String())
+	}
+	return nil
+}
+
+func isTextEmpty(t *TextConstruct) bool {
+	return t == nil || (t.Value == "" && t.ValueXML == "")
+}
+
+func checkText(t *TextConstruct) error {
+	if t == nil {
+		return nil
+	}
+	switch t.Type {
+	case "", "text", "html":
+		if t.ValueXML != "" {
+			return fmt.Errorf("field %q must be empty when using type %q — use field %q instead", "ValueXML", t.Type, "Value")
+		}
+	case "xhtml":
+		if t.Value != "" {
+			return fmt.Errorf("field %q must be empty when using type %q — use field %q instead", "Value", "xhtml", "ValueXML")
+		}
+		return checkXHTML(t.ValueXML)
+	default:
+		return fmt.Errorf("invalid text type %q", t.Type)
 	}
 	return nil
 }
@@ -211,6 +278,9 @@ func checkContent(c *Content) error {
 		if c.Value != "" {
 			return fmt.Errorf("field %q must be empty when using type %q — use field %q instead", "Value", "xhtml", "ValueXML")
 		}
+		if c.Source == "" {
+			return checkXHTML(c.ValueXML)
+		}
 	default:
 		// Whatever a media type is, it contains at least one slash
 		if strings.Contains(c.Type, "/") == false {
@@ -56,13 +56,14 @@ func Test_checkPerson(t *testing.T) {
 func Test_checkDate(t *testing.T) {
 	tests := []struct {
 		name    string
-		date    string
+		date    *Date
 		wantErr bool
 	}{
-		{"empty", "", true},
-		{"zero", time.Time{}.Format(time.RFC3339), true},
-		{"notrfc", time.Now().Format(time.UnixDate), true},
-		{"valid", time.Now().Format(time.RFC3339), false},
+		{"nil", nil, true},
+		{"zero", &Date{}, true},
+		{"valid", NewDate(time.Now()), false},
+		{"deprecated value", &Date{Value: "2012-12-21T08:30:15Z"}, false},
+		{"invalid value", &Date{Value: "yesterday"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
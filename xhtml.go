@@ -0,0 +1,185 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const nsXHTML = "http://www.w3.org/1999/xhtml"
+
+// XHTMLContent converts (possibly malformed) HTML into well-formed XHTML and returns
+// an atom:content element of type "xhtml".
+// The content gets wrapped into the mandatory XHTML div element, unless it already is.
+//  https://tools.ietf.org/html/rfc4287#section-4.1.3.3
+func XHTMLContent(html []byte) *Content {
+	return &Content{Type: "xhtml", ValueXML: WrapXHTML(string(html))}
+}
+
+// XHTMLText converts (possibly malformed) HTML into well-formed XHTML and returns
+// a text construct of type "xhtml" suitable for titles and subtitles.
+//  https://tools.ietf.org/html/rfc4287#section-3.1.1.3
+func XHTMLText(html string) *TextConstruct {
+	return &TextConstruct{Type: "xhtml", ValueXML: WrapXHTML(html)}
+}
+
+// WrapXHTML converts (possibly malformed) HTML into well-formed XHTML,
+// which is wrapped into a single div element of the XHTML namespace.
+// An existing XHTML div wrapper is replaced and not wrapped a second time.
+func WrapXHTML(html string) string {
+	return `<div xmlns="` + nsXHTML + `">` + UnwrapXHTML(html) + `</div>`
+}
+
+// UnwrapXHTML converts (possibly malformed) HTML into well-formed XHTML
+// and removes the XHTML div wrapper, if there is one.
+func UnwrapXHTML(html string) string {
+	tokens := htmlTokens(html)
+	if start, end, ok := xhtmlWrapper(tokens); ok {
+		tokens = tokens[start+1 : end]
+	}
+	return writeXHTML(tokens)
+}
+
+// xhtmlWrapper returns the indices of the start and end tokens of the XHTML div wrapper,
+// if the tokens consist of a single div element in the XHTML namespace surrounded by whitespace.
+func xhtmlWrapper(tokens []xml.Token) (start, end int, ok bool) {
+	start, end = -1, -1
+	depth := 0
+	for i, t := range tokens {
+		switch t := t.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if start != -1 || t.Name.Local != "div" || t.Name.Space != nsXHTML {
+					return 0, 0, false
+				}
+				start = i
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		case xml.CharData:
+			if depth == 0 && strings.TrimSpace(string(t)) != "" {
+				return 0, 0, false
+			}
+		case xml.Comment:
+			if depth == 0 {
+				return 0, 0, false
+			}
+		}
+	}
+	return start, end, start != -1
+}
+
+// writeXHTML writes balanced tokens as XHTML fragment, which is valid within an XHTML div.
+// Element and attribute names are lowercased, elements of foreign namespaces (like SVG)
+// keep their namespace and attributes of unknown namespaces are dropped.
+// Elements with names, which are invalid in XML, are replaced by their content, invalid
+// attributes and characters are dropped and "--" is removed from comments,
+// so that the fragment is always well-formed.
+func writeXHTML(tokens []xml.Token) string {
+	b := &strings.Builder{}
+	spaces := []string{nsXHTML}
+	dropped := []bool{} // whether the open elements are dropped
+	for i := 0; i < len(tokens); i++ {
+		switch t := tokens[i].(type) {
+		case xml.StartElement:
+			space := t.Name.Space
+			if space == "" || strings.Contains(space, ":") == false { // no or undeclared namespace
+				space = spaces[len(spaces)-1]
+			}
+			name := t.Name.Local
+			if space == nsXHTML {
+				name = strings.ToLower(name)
+			}
+			if isNCName(name) == false || space == nsXML || space == nsXMLNS {
+				dropped = append(dropped, true)
+				continue
+			}
+			dropped = append(dropped, false)
+			b.WriteString("<" + name)
+			if space != spaces[len(spaces)-1] {
+				b.WriteString(` xmlns="` + attrEscaper.Replace(xmlChars(space)) + `"`)
+			}
+			spaces = append(spaces, space)
+			seen := map[string]bool{}
+			for _, attr := range t.Attr {
+				attrName := prefixed(attr.Name)
+				if attrName.Space != "" || attrName.Local == "xmlns" {
+					continue
+				}
+				local := attrName.Local
+				if space == nsXHTML {
+					local = strings.ToLower(local)
+				}
+				if seen[local] || isNCName(strings.TrimPrefix(local, "xml:")) == false { // only the xml prefix is always declared
+					continue
+				}
+				seen[local] = true
+				b.WriteString(" " + local + `="` + attrEscaper.Replace(xmlChars(attr.Value)) + `"`)
+			}
+			if end, ok := tokens[i+1].(xml.EndElement); ok && voidElements[name] && end.Name.Local == t.Name.Local {
+				b.WriteString("/>")
+				spaces = spaces[:len(spaces)-1]
+				dropped = dropped[:len(dropped)-1]
+				i++
+				continue
+			}
+			b.WriteString(">")
+		case xml.EndElement:
+			drop := dropped[len(dropped)-1]
+			dropped = dropped[:len(dropped)-1]
+			if drop {
+				continue
+			}
+			name := t.Name.Local
+			if spaces[len(spaces)-1] == nsXHTML {
+				name = strings.ToLower(name)
+			}
+			spaces = spaces[:len(spaces)-1]
+			b.WriteString("</" + name + ">")
+		case xml.CharData:
+			b.WriteString(textEscaper.Replace(xmlChars(string(t))))
+		case xml.Comment:
+			b.WriteString("<!--" + commentText(string(t)) + "-->")
+		}
+	}
+	return b.String()
+}
+
+// commentText returns the text of a comment without "--" and without a trailing "-",
+// which are not allowed within XML comments.
+func commentText(s string) string {
+	s = xmlChars(s)
+	for strings.Contains(s, "--") {
+		s = strings.Replace(s, "--", "-", -1)
+	}
+	if strings.HasSuffix(s, "-") {
+		s += " "
+	}
+	return s
+}
+
+// checkXHTML verifies that value is well-formed XML, which consists of a single XHTML div element.
+//  https://tools.ietf.org/html/rfc4287#section-3.1.1.3
+func checkXHTML(value string) error {
+	d := xml.NewDecoder(strings.NewReader(value))
+	tokens := []xml.Token{}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid xhtml: %v", err)
+		}
+		tokens = append(tokens, xml.CopyToken(t))
+	}
+	if _, _, ok := xhtmlWrapper(tokens); ok == false {
+		return fmt.Errorf("invalid xhtml: content must be wrapped in a single div element of namespace %q", nsXHTML)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package atomfeed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrapXHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"text", "Go &amp; gophers", `<div xmlns="http://www.w3.org/1999/xhtml">Go &amp; gophers</div>`},
+		{"fragment", "<p>one</p><p>two</p>", `<div xmlns="http://www.w3.org/1999/xhtml"><p>one</p><p>two</p></div>`},
+		{"already wrapped", ` <div xmlns="http://www.w3.org/1999/xhtml"><p>Developers</p></div>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p>Developers</p></div>`},
+		{"plain div", `<div class="post"><p>Developers</p></div>`, `<div xmlns="http://www.w3.org/1999/xhtml"><div class="post"><p>Developers</p></div></div>`},
+		{"void elements", `line<br>break <img src="gopher.png" alt="Go Pher">`, `<div xmlns="http://www.w3.org/1999/xhtml">line<br/>break <img src="gopher.png" alt="Go Pher"/></div>`},
+		{"unclosed elements", `<P CLASS=intro>one<p>two`, `<div xmlns="http://www.w3.org/1999/xhtml"><p class="intro">one</p><p>two</p></div>`},
+		{"html entities", `&copy; 2017&nbsp;Go &lt;3`, "<div xmlns=\"http://www.w3.org/1999/xhtml\">© 2017\u00a0Go &lt;3</div>"},
+		{"boolean attribute", `<input disabled>`, `<div xmlns="http://www.w3.org/1999/xhtml"><input disabled="disabled"/></div>`},
+		{"inline svg", `<svg xmlns="http://www.w3.org/2000/svg"><rect width="10"/></svg>`, `<div xmlns="http://www.w3.org/1999/xhtml"><svg xmlns="http://www.w3.org/2000/svg"><rect width="10"></rect></svg></div>`},
+		{"stray end tag", `<p>one</span></p>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p>one</p></div>`},
+		{"bare less-than", `a < b && c<3`, `<div xmlns="http://www.w3.org/1999/xhtml">a &lt; b &amp;&amp; c&lt;3</div>`},
+		{"unquoted attributes", `<img src=a.png alt=Go onerror=alert(1)>`, `<div xmlns="http://www.w3.org/1999/xhtml"><img src="a.png" alt="Go" onerror="alert(1)"/></div>`},
+		{"list items", `<ul><li>one<li>two</ul>`, `<div xmlns="http://www.w3.org/1999/xhtml"><ul><li>one</li><li>two</li></ul></div>`},
+		{"double hyphen in comment", `a<!-- a -- b -->b`, `<div xmlns="http://www.w3.org/1999/xhtml">a<!-- a - b -->b</div>`},
+		{"hyphen ending comment", `<!-- a --->`, `<div xmlns="http://www.w3.org/1999/xhtml"><!-- a - --></div>`},
+		{"quoted attribute name", `<p "x">a</p>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p>a</p></div>`},
+		{"attribute name starting with digit", `<p 1a=2>a</p>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p>a</p></div>`},
+		{"less-than in attribute name", `<p a<b=1>a</p>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p>a</p></div>`},
+		{"less-than in element name", `<a<b>a</a<b>`, `<div xmlns="http://www.w3.org/1999/xhtml">a</div>`},
+		{"invalid utf-8 in element name", "<p\xff>a</p>", `<div xmlns="http://www.w3.org/1999/xhtml">a</div>`},
+		{"xml namespace element", `<xml:basex>a</xml:basex>`, `<div xmlns="http://www.w3.org/1999/xhtml">a</div>`},
+		{"undeclared attribute prefix", `<p media:x="1" xml:lang="en">a</p>`, `<div xmlns="http://www.w3.org/1999/xhtml"><p xml:lang="en">a</p></div>`},
+		{"illegal characters", "a\x00b\x01c", `<div xmlns="http://www.w3.org/1999/xhtml">abc</div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapXHTML(tt.html)
+			if got != tt.want {
+				t.Errorf("WrapXHTML() = %v, want %v", got, tt.want)
+			}
+			if err := checkXHTML(got); err != nil {
+				t.Errorf("WrapXHTML() returned invalid xhtml: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnwrapXHTML(t *testing.T) {
+	got := UnwrapXHTML(`<div xmlns="http://www.w3.org/1999/xhtml"><p>Developers <em>Developers</em></p></div>`)
+	if want := `<p>Developers <em>Developers</em></p>`; got != want {
+		t.Errorf("UnwrapXHTML() = %v, want %v", got, want)
+	}
+}
+
+func Test_checkXHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"valid", `<div xmlns="http://www.w3.org/1999/xhtml"><p>Developers</p></div>`, false},
+		{"missing namespace", `<div><p>Developers</p></div>`, true},
+		{"missing wrapper", `<p>Developers</p>`, true},
+		{"two wrappers", `<div xmlns="http://www.w3.org/1999/xhtml"></div><div xmlns="http://www.w3.org/1999/xhtml"></div>`, true},
+		{"malformed", `<div xmlns="http://www.w3.org/1999/xhtml"><p>Developers</div>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkXHTML(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("checkXHTML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestXHTMLText(t *testing.T) {
+	title := XHTMLText("Go <em>1.10</em> released")
+	entry := Entry{ID: NewID("tag:example.com,2005:blog.post-1"), Title: title, Updated: NewDate(time.Date(2012, time.December, 21, 8, 30, 15, 0, time.UTC)), Content: NewContent("xhtml", "", []byte("<p>Release notes"))}
+	if err := entry.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	feed := Feed{Entries: []Entry{entry}}
+	out := &bytes.Buffer{}
+	if err := feed.Encode(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Go <em>1.10</em> released</div></title>`,
+		`<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Release notes</p></div></content>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Encode() output is missing %s\n\ngot:\n%v", want, out.String())
+		}
+	}
+	decoded, err := Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Entries[0].Title; got.ValueXML != title.ValueXML || got.Value != "" {
+		t.Errorf("Decode() title = %+v, want %+v", got, title)
+	}
+	if got := textValue(title); got != "Go 1.10 released" {
+		t.Errorf("textValue() = %q, want %q", got, "Go 1.10 released")
+	}
+
+	entry.Title = &TextConstruct{Type: "xhtml", ValueXML: "<p>Go</p>"}
+	if err := entry.Verify(); err == nil {
+		t.Error("expected an error on missing xhtml div wrapper, got none")
+	}
+}
+
+func TestXHTMLContentEncode(t *testing.T) {
+	for _, html := range []string{`<!-- a -- b -->`, `<p "x">a</p>`, `<p 1a=2>`, `<p a<b=1>`, `<a<b>`, `<xml:basex>a`, "a\x00b"} {
+		feed := Feed{Entries: []Entry{{Content: XHTMLContent([]byte(html))}}}
+		if err := feed.Encode(&bytes.Buffer{}); err != nil {
+			t.Errorf("Encode() of XHTMLContent(%q) error = %v", html, err)
+		}
+	}
+}
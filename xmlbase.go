@@ -0,0 +1,394 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// Absolutize resolves all relative references of the feed (links, icon, logo, content
+// sources, person URIs, Media RSS objects and thumbnails and URLs within html and xhtml content)
+// into absolute URLs.
+//
+// References are resolved against the effective xml:base of their element,
+// which is computed according to the XML Base rules: an element's xml:base attribute
+// is resolved against the base of its parent element, with documentURI
+// (the location of the feed document) acting as base of the atom:feed element.
+// Only the URL attributes of html content change, the rest of its markup is kept as written.
+//  https://www.w3.org/TR/xmlbase/
+//  https://tools.ietf.org/html/rfc4287#section-2
+func (f *Feed) Absolutize(documentURI string) error {
+	doc, err := url.Parse(documentURI)
+	if err != nil {
+		return err
+	}
+	r := &urlRewriter{rewrite: func(base *url.URL, ref string) (string, error) {
+		u, err := base.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	}}
+	return r.feed(doc, f)
+}
+
+// Relativize makes all references of the feed relative to base in order to shrink the output.
+// The feed's xml:base attribute is set to base, while all other xml:base attributes are removed.
+// References to other hosts remain absolute.
+func (f *Feed) Relativize(base string) error {
+	root, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+	r := &urlRewriter{dropBase: true, rewrite: func(elementBase *url.URL, ref string) (string, error) {
+		u, err := elementBase.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		return relativeURL(root, u), nil
+	}}
+	if err := r.feed(root, f); err != nil {
+		return err
+	}
+	if f.CommonAttributes == nil {
+		f.CommonAttributes = &CommonAttributes{}
+	}
+	f.Base = base
+	return nil
+}
+
+// urlRewriter walks all elements of a feed, keeps track of their effective
+// xml:base and rewrites all references.
+type urlRewriter struct {
+	rewrite  func(base *url.URL, ref string) (string, error)
+	dropBase bool // remove xml:base attributes after computing the effective base
+}
+
+// base returns the effective base of an element with the given attributes.
+func (r *urlRewriter) base(parent *url.URL, ca *CommonAttributes) (*url.URL, error) {
+	if ca == nil || ca.Base == "" {
+		return parent, nil
+	}
+	base, err := parent.Parse(ca.Base)
+	if err != nil {
+		return nil, err
+	}
+	if r.dropBase {
+		ca.Base = ""
+	}
+	return base, nil
+}
+
+// ref rewrites a single reference of an element with the given attributes.
+func (r *urlRewriter) ref(parent *url.URL, ca *CommonAttributes, ref *string) error {
+	base, err := r.base(parent, ca)
+	if err != nil || *ref == "" {
+		return err
+	}
+	*ref, err = r.rewrite(base, *ref)
+	return err
+}
+
+func (r *urlRewriter) feed(doc *url.URL, f *Feed) error {
+	base, err := r.base(doc, f.CommonAttributes)
+	if err != nil {
+		return err
+	}
+	if err := r.metadata(base, f.Links, f.Icon, f.Logo, f.Generator, f.Author, f.Contributor); err != nil {
+		return err
+	}
+	for i := range f.Entries {
+		if err := r.entry(base, &f.Entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *urlRewriter) entry(parent *url.URL, e *Entry) error {
+	base, err := r.base(parent, e.CommonAttributes)
+	if err != nil {
+		return err
+	}
+	if err := r.metadata(base, e.Links, nil, nil, nil, e.Author, e.Contributor); err != nil {
+		return err
+	}
+	if s := e.Source; s != nil {
+		sourceBase, err := r.base(base, s.CommonAttributes)
+		if err != nil {
+			return err
+		}
+		if err := r.metadata(sourceBase, s.Links, s.Icon, s.Logo, s.Generator, s.Author, s.Contributor); err != nil {
+			return err
+		}
+	}
+	for _, c := range []*Content{e.Summary, e.Content} {
+		if err := r.content(base, c); err != nil {
+			return err
+		}
+	}
+	if e.Media != nil {
+		return r.media(base, e.Media)
+	}
+	return nil
+}
+
+// media rewrites the URLs of media objects and thumbnails and the URLs within html descriptions.
+// Media RSS elements don't carry xml:base attributes and inherit the base of their entry.
+func (r *urlRewriter) media(base *url.URL, m *Media) error {
+	metadata := []*MediaMetadata{&m.MediaMetadata}
+	for i := range m.Groups {
+		metadata = append(metadata, &m.Groups[i].MediaMetadata)
+		for j := range m.Groups[i].Contents {
+			c := &m.Groups[i].Contents[j]
+			if err := r.ref(base, nil, &c.URL); err != nil {
+				return err
+			}
+			metadata = append(metadata, &c.MediaMetadata)
+		}
+	}
+	for i := range m.Contents {
+		c := &m.Contents[i]
+		if err := r.ref(base, nil, &c.URL); err != nil {
+			return err
+		}
+		metadata = append(metadata, &c.MediaMetadata)
+	}
+	for _, md := range metadata {
+		for i := range md.Thumbnails {
+			if err := r.ref(base, nil, &md.Thumbnails[i].URL); err != nil {
+				return err
+			}
+		}
+		for _, t := range []*MediaText{md.Title, md.Description} {
+			if t == nil || t.Type != "html" {
+				continue
+			}
+			var err error
+			if t.Value, err = r.html(base, t.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// metadata rewrites the references of the elements shared by atom:feed, atom:entry and atom:source.
+func (r *urlRewriter) metadata(base *url.URL, links []Link, icon *Icon, logo *Logo, generator *Generator, author *Person, contributors []Person) error {
+	for i := range links {
+		if err := r.ref(base, links[i].CommonAttributes, &links[i].Href); err != nil {
+			return err
+		}
+	}
+	if icon != nil {
+		if err := r.ref(base, icon.CommonAttributes, &icon.Value); err != nil {
+			return err
+		}
+	}
+	if logo != nil {
+		if err := r.ref(base, logo.CommonAttributes, &logo.Value); err != nil {
+			return err
+		}
+	}
+	if generator != nil {
+		if err := r.ref(base, generator.CommonAttributes, &generator.URI); err != nil {
+			return err
+		}
+	}
+	if author != nil {
+		if err := r.ref(base, author.CommonAttributes, &author.URI); err != nil {
+			return err
+		}
+	}
+	for i := range contributors {
+		if err := r.ref(base, contributors[i].CommonAttributes, &contributors[i].URI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *urlRewriter) content(parent *url.URL, c *Content) error {
+	if c == nil {
+		return nil
+	}
+	base, err := r.base(parent, c.CommonAttributes)
+	if err != nil {
+		return err
+	}
+	if c.Source != "" {
+		if c.Source, err = r.rewrite(base, c.Source); err != nil {
+			return err
+		}
+	}
+	switch c.Type {
+	case "html":
+		c.Value, err = r.html(base, c.Value)
+	case "xhtml":
+		var inner string
+		if inner, err = r.xhtml(base, UnwrapXHTML(c.ValueXML)); err == nil {
+			c.ValueXML = WrapXHTML(inner)
+		}
+	}
+	return err
+}
+
+// html rewrites all URL attributes within HTML markup. Only the values of the URL attributes
+// are replaced, the rest of the markup (including raw text of script and style elements) is kept as written.
+func (r *urlRewriter) html(base *url.URL, markup string) (string, error) {
+	z := &htmlTokenizer{s: markup}
+	b := &strings.Builder{}
+	written := 0
+	for {
+		t, ok := z.next()
+		if ok == false {
+			break
+		}
+		tag, ok := t.(htmlTag)
+		if ok == false || tag.end {
+			continue
+		}
+		if rawTextElements[strings.ToLower(tag.name)] {
+			z.rawText = strings.ToLower(tag.name)
+		}
+		for _, attr := range tag.attrs {
+			name := strings.ToLower(attr.name)
+			if attr.pos == 0 || urlAttributes[name] == false {
+				continue
+			}
+			value, err := r.refs(base, attr.value, name == "srcset")
+			if err != nil {
+				return "", err
+			}
+			if value == attr.value {
+				continue
+			}
+			b.WriteString(markup[written:attr.pos])
+			if attr.quoted {
+				b.WriteString(html.EscapeString(value))
+			} else {
+				b.WriteString(`"` + html.EscapeString(value) + `"`)
+			}
+			written = attr.pos + len(attr.raw)
+		}
+	}
+	b.WriteString(markup[written:])
+	return b.String(), nil
+}
+
+// xhtml rewrites all URL attributes within XHTML markup
+// and respects xml:base attributes of its elements.
+func (r *urlRewriter) xhtml(base *url.URL, xhtml string) (string, error) {
+	tokens := htmlTokens(xhtml)
+	bases := []*url.URL{base}
+	var err error
+	for i, t := range tokens {
+		switch t := t.(type) {
+		case xml.StartElement:
+			elementBase := bases[len(bases)-1]
+			attrs := []xml.Attr{}
+			for _, attr := range t.Attr {
+				if prefixed(attr.Name).Local == "xml:base" {
+					if elementBase, err = elementBase.Parse(attr.Value); err != nil {
+						return "", err
+					}
+					if r.dropBase {
+						continue
+					}
+				}
+				attrs = append(attrs, attr)
+			}
+			for i, attr := range attrs {
+				name := strings.ToLower(attr.Name.Local)
+				if attr.Name.Space != "" || urlAttributes[name] == false {
+					continue
+				}
+				if attrs[i].Value, err = r.refs(elementBase, attr.Value, name == "srcset"); err != nil {
+					return "", err
+				}
+			}
+			t.Attr = attrs
+			tokens[i] = t
+			bases = append(bases, elementBase)
+		case xml.EndElement:
+			bases = bases[:len(bases)-1]
+		}
+	}
+	return writeXHTML(tokens), nil
+}
+
+// refs rewrites a single reference or – for srcset attributes – a list of image candidates.
+func (r *urlRewriter) refs(base *url.URL, value string, srcset bool) (string, error) {
+	if srcset == false {
+		return r.rewrite(base, strings.TrimSpace(value))
+	}
+	candidates := strings.Split(value, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		ref, err := r.rewrite(base, fields[0])
+		if err != nil {
+			return "", err
+		}
+		candidates[i] = strings.Join(append([]string{ref}, fields[1:]...), " ")
+	}
+	return strings.Join(candidates, ", "), nil
+}
+
+// relativeURL returns the shortest reference to target, which resolves to target against base.
+func relativeURL(base, target *url.URL) string {
+	if target.Scheme != base.Scheme || target.User.String() != base.User.String() || target.Host != base.Host || target.Opaque != "" || base.Opaque != "" {
+		return target.String()
+	}
+	suffix := ""
+	if target.RawQuery != "" || target.ForceQuery {
+		suffix = "?" + target.RawQuery
+	}
+	if target.Fragment != "" {
+		suffix += "#" + target.EscapedFragment()
+	}
+	basePath, targetPath := base.EscapedPath(), target.EscapedPath()
+	if targetPath == "" {
+		targetPath = "/"
+	}
+	if basePath == "" {
+		basePath = "/"
+	}
+	if targetPath == basePath && (target.RawQuery != "" || target.ForceQuery || target.Fragment != "") {
+		if base.RawQuery == target.RawQuery && target.Fragment != "" && target.ForceQuery == false {
+			return "#" + target.EscapedFragment()
+		}
+		if target.RawQuery != "" || target.ForceQuery {
+			return lastSegment(targetPath) + suffix
+		}
+	}
+	candidates := []string{targetPath + suffix} // path-absolute reference
+	baseDirs := strings.Split(basePath[:strings.LastIndex(basePath, "/")], "/")
+	targetDirs := strings.Split(targetPath[:strings.LastIndex(targetPath, "/")], "/")
+	common := 0
+	for common < len(baseDirs) && common < len(targetDirs) && baseDirs[common] == targetDirs[common] {
+		common++
+	}
+	rel := strings.Repeat("../", len(baseDirs)-common) + strings.Join(append(targetDirs[common:], lastSegment(targetPath)), "/")
+	rel = strings.TrimPrefix(rel, "/")
+	if rel == "" {
+		rel = "./"
+	} else if first := strings.SplitN(rel, "/", 2)[0]; strings.Contains(first, ":") {
+		rel = "./" + rel // a colon within the first segment would be taken for a scheme
+	}
+	candidates = append(candidates, rel+suffix)
+	shortest := candidates[0]
+	for _, c := range candidates[1:] {
+		if len(c) < len(shortest) {
+			shortest = c
+		}
+	}
+	return shortest
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
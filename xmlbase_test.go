@@ -0,0 +1,125 @@
+package atomfeed
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFeedAbsolutize(t *testing.T) {
+	feed := Feed{
+		CommonAttributes: &CommonAttributes{Base: "/blog/"},
+		Links:            []Link{{Href: "feed.atom", Rel: "self"}},
+		Icon:             &Icon{Value: "/favicon.ico"},
+		Entries: []Entry{{
+			CommonAttributes: &CommonAttributes{Base: "2017/"},
+			Links:            []Link{{Href: "post-1", Rel: "alternate"}, {Href: "https://other.org/", Rel: "related"}},
+			Content:          &Content{Type: "html", Value: `<a href="../about">About</a><img src="gopher.png" srcset="gopher.png 1x, gopher@2x.png 2x">`},
+			Summary:          &Content{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml"><p xml:base="/img/"><img src="a.png"/></p></div>`},
+			Media: &Media{
+				Contents: []MediaContent{{URL: "talk.mp4", MediaMetadata: MediaMetadata{Thumbnails: []MediaThumbnail{NewMediaThumbnail("talk.jpg", 640, 360)}}}},
+				Groups:   []MediaGroup{{Contents: []MediaContent{NewMediaContent("/photos/gopher.jpg", "image/jpeg", "image")}}},
+				MediaMetadata: MediaMetadata{
+					Description: &MediaText{Type: "html", Value: `Slides: <a href=slides.pdf>pdf</a>`},
+				},
+			},
+		}, {
+			Content: &Content{Type: "html", Value: `a < b <img src=img/a.png alt=a.png>`},
+		}, {
+			Content: &Content{Type: "html", Value: `<p>Line<br>break<script>if (a < b && c > d) { x = "<a href='x'>" }</script><style>a > b { }</style><a title='a "quote"' HREF='q?a=1&amp;b=2'>q</a><!-- <a href="c"> --></p>`},
+		}},
+	}
+	if err := feed.Absolutize("https://example.com/index.atom"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"feed link", feed.Links[0].Href, "https://example.com/blog/feed.atom"},
+		{"icon", feed.Icon.Value, "https://example.com/favicon.ico"},
+		{"entry link", feed.Entries[0].Links[0].Href, "https://example.com/blog/2017/post-1"},
+		{"absolute link", feed.Entries[0].Links[1].Href, "https://other.org/"},
+		{"html content", feed.Entries[0].Content.Value, `<a href="https://example.com/blog/about">About</a><img src="https://example.com/blog/2017/gopher.png" srcset="https://example.com/blog/2017/gopher.png 1x, https://example.com/blog/2017/gopher@2x.png 2x">`},
+		{"xhtml content", feed.Entries[0].Summary.ValueXML, `<div xmlns="http://www.w3.org/1999/xhtml"><p xml:base="/img/"><img src="https://example.com/img/a.png"/></p></div>`},
+		{"media content", feed.Entries[0].Media.Contents[0].URL, "https://example.com/blog/2017/talk.mp4"},
+		{"media thumbnail", feed.Entries[0].Media.Contents[0].Thumbnails[0].URL, "https://example.com/blog/2017/talk.jpg"},
+		{"media group", feed.Entries[0].Media.Groups[0].Contents[0].URL, "https://example.com/photos/gopher.jpg"},
+		{"media description", feed.Entries[0].Media.Description.Value, `Slides: <a href="https://example.com/blog/2017/slides.pdf">pdf</a>`},
+		{"malformed html", feed.Entries[1].Content.Value, `a < b <img src="https://example.com/blog/img/a.png" alt=a.png>`},
+		{"html markup kept", feed.Entries[2].Content.Value, `<p>Line<br>break<script>if (a < b && c > d) { x = "<a href='x'>" }</script><style>a > b { }</style><a title='a "quote"' HREF='https://example.com/blog/q?a=1&amp;b=2'>q</a><!-- <a href="c"> --></p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Absolutize() = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedRelativize(t *testing.T) {
+	feed := Feed{
+		Links: []Link{{Href: "https://example.com/blog/feed.atom", Rel: "self"}},
+		Entries: []Entry{{
+			CommonAttributes: &CommonAttributes{Base: "https://example.com/blog/2017/"},
+			Links:            []Link{{Href: "post-1", Rel: "alternate"}, {Href: "https://other.org/", Rel: "related"}},
+			Content:          &Content{Type: "html", Value: `<a href="/about">About</a>`},
+			Media:            &Media{Contents: []MediaContent{NewMediaContent("https://example.com/blog/2017/talk.mp4", "video/mp4", "video")}},
+		}},
+	}
+	if err := feed.Relativize("https://example.com/blog/"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"feed base", feed.Base, "https://example.com/blog/"},
+		{"entry base", feed.Entries[0].Base, ""},
+		{"feed link", feed.Links[0].Href, "feed.atom"},
+		{"entry link", feed.Entries[0].Links[0].Href, "2017/post-1"},
+		{"other host", feed.Entries[0].Links[1].Href, "https://other.org/"},
+		{"html content", feed.Entries[0].Content.Value, `<a href="/about">About</a>`},
+		{"media content", feed.Entries[0].Media.Contents[0].URL, "2017/talk.mp4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Relativize() = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_relativeURL(t *testing.T) {
+	base := "https://example.com/blog/2017/index.html?page=2"
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"https://example.com/blog/2017/post-1", "post-1"},
+		{"https://example.com/blog/2017/", "./"},
+		{"https://example.com/blog/about", "../about"},
+		{"https://example.com/", "/"},
+		{"https://example.com/blog/2017/index.html?page=3", "index.html?page=3"},
+		{"https://example.com/blog/2017/index.html?page=2#top", "#top"},
+		{"https://example.com/blog/2017/a:b", "./a:b"},
+		{"http://example.com/blog/", "http://example.com/blog/"},
+		{"https://example.org/blog/", "https://example.org/blog/"},
+	}
+	b, _ := url.Parse(base)
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			got := relativeURL(b, target)
+			if got != tt.want {
+				t.Errorf("relativeURL() = %v, want %v", got, tt.want)
+			}
+			if resolved, _ := b.Parse(got); resolved.String() != tt.target {
+				t.Errorf("relativeURL() = %v resolves to %v, want %v", got, resolved, tt.target)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package atomfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// isXMLChar reports whether r is a character, which is allowed in XML 1.0 documents.
+//  https://www.w3.org/TR/xml/#charsets
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= utf8.MaxRune
+}
+
+// isNCName reports whether s is an XML name without colon, which is valid as element or attribute name.
+// Names are checked with encoding/xml, which follows the stricter name characters of earlier XML 1.0 editions.
+//  https://www.w3.org/TR/xml-names/#NT-NCName
+func isNCName(s string) bool {
+	if s == "" || strings.ContainsAny(s, ":<>/=\"'&") || utf8.ValidString(s) == false {
+		return false
+	}
+	t, err := xml.NewDecoder(strings.NewReader("<" + s + "/>")).RawToken()
+	start, ok := t.(xml.StartElement)
+	return err == nil && ok && start.Name.Local == s && len(start.Attr) == 0
+}
+
+// xmlChars removes characters, which are illegal in XML 1.0, and invalid UTF-8 from s.
+func xmlChars(s string) string {
+	s, _ = stripIllegalChars(s)
+	return s
+}
+
+// stripIllegalChars removes characters, which are illegal in XML 1.0, and invalid UTF-8 from s.
+// It returns the cleaned string and a description of the removed characters.
+func stripIllegalChars(s string) (string, []string) {
+	clean := true
+	for i, r := range s {
+		if isXMLChar(r) == false || (r == utf8.RuneError && strings.HasPrefix(s[i:], "\uFFFD") == false) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s, nil
+	}
+	b := &strings.Builder{}
+	removed := []string{}
+	seen := map[string]bool{}
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		char := ""
+		switch {
+		case r == utf8.RuneError && size == 1:
+			char = fmt.Sprintf("invalid UTF-8 byte %#x", s[0])
+		case isXMLChar(r) == false:
+			char = fmt.Sprintf("%U", r)
+		default:
+			b.WriteString(s[:size])
+		}
+		if char != "" && seen[char] == false {
+			seen[char] = true
+			removed = append(removed, char)
+		}
+		s = s[size:]
+	}
+	return b.String(), removed
+}
+
+// checkFragment checks whether a raw XML fragment, e.g. the value of Content.ValueXML, is well-formed.
+func checkFragment(fragment string) error {
+	d := xml.NewDecoder(strings.NewReader("<fragment>" + fragment + "</fragment>"))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// xmlCleaner prepares a feed for encoding: it removes characters, which are illegal
+// in XML 1.0, from a copy of the feed and checks all raw XML fragments.
+type xmlCleaner struct {
+	changes []string
+}
+
+var (
+	contentType = reflect.TypeOf(Content{})
+	textType    = reflect.TypeOf(TextConstruct{})
+	entryType   = reflect.TypeOf(Entry{})
+)
+
+// clean returns the feed or, if characters had to be removed, a cleaned copy
+// together with a description of all changes.
+func (f *Feed) clean() (*Feed, []string, error) {
+	c := &xmlCleaner{}
+	v, _, err := c.value(reflect.ValueOf(f), "feed")
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.Interface().(*Feed), c.changes, nil
+}
+
+// value returns v or a cleaned copy of v, if any string within v contains illegal characters,
+// and reports whether v was copied.
+// Path describes the location of v for errors and changes, e.g. "entry <id>: content".
+func (c *xmlCleaner) value(v reflect.Value, path string) (reflect.Value, bool, error) {
+	switch v.Kind() {
+	case reflect.String:
+		s, removed := stripIllegalChars(v.String())
+		if removed == nil {
+			return v, false, nil
+		}
+		c.changes = append(c.changes, fmt.Sprintf("%v: removed illegal characters %v", path, strings.Join(removed, ", ")))
+		return reflect.ValueOf(s).Convert(v.Type()), true, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, false, nil
+		}
+		elem, changed, err := c.value(v.Elem(), path)
+		if err != nil || changed == false {
+			return v, false, err
+		}
+		p := reflect.New(elem.Type())
+		p.Elem().Set(elem)
+		return p, true, nil
+	case reflect.Slice:
+		var s reflect.Value // copy of the slice, created on the first change
+		for i := 0; i < v.Len(); i++ {
+			elemPath := path
+			if v.Type().Elem() == entryType {
+				elemPath = "entry " + v.Index(i).Interface().(Entry).ID.Value
+			}
+			elem, changed, err := c.value(v.Index(i), elemPath)
+			if err != nil {
+				return v, false, err
+			}
+			if changed {
+				if s.IsValid() == false {
+					s = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+					reflect.Copy(s, v)
+				}
+				s.Index(i).Set(elem)
+			}
+		}
+		if s.IsValid() {
+			return s, true, nil
+		}
+		return v, false, nil
+	case reflect.Struct:
+		var s reflect.Value // copy of the struct, created on the first change
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			value, changed, err := c.value(v.Field(i), fieldPath(path, field))
+			if err != nil {
+				return v, false, err
+			}
+			if changed {
+				if s.IsValid() == false {
+					s = reflect.New(v.Type()).Elem()
+					s.Set(v)
+				}
+				s.Field(i).Set(value)
+			}
+		}
+		changed := s.IsValid()
+		if changed {
+			v = s
+		}
+		if v.Type() == contentType || v.Type() == textType {
+			if raw := v.FieldByName("ValueXML").String(); raw != "" {
+				if err := checkFragment(raw); err != nil {
+					return v, false, fmt.Errorf("%v: malformed XML: %v", path, err)
+				}
+			}
+		}
+		return v, changed, nil
+	}
+	return v, false, nil
+}
+
+// fieldPath appends the XML name of a struct field to path.
+func fieldPath(path string, field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("xml"), ",")[0]
+	if name == "" || name == "-" || field.Anonymous {
+		return path
+	}
+	if i := strings.LastIndex(name, ">"); i >= 0 {
+		name = name[i+1:]
+	}
+	return path + ": " + name
+}
@@ -0,0 +1,99 @@
+package atomfeed
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_stripIllegalChars(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		want        string
+		wantRemoved []string
+	}{
+		{"clean", "Fish & Chips\t\r\n", "Fish & Chips\t\r\n", nil},
+		{"replacement character", "\uFFFD", "\uFFFD", nil},
+		{"control characters", "a\x00b\x08c\x1bd\x08", "abcd", []string{"U+0000", "U+0008", "U+001B"}},
+		{"non characters", "a\uFFFEb", "ab", []string{"U+FFFE"}},
+		{"invalid utf-8", "a\xffb", "ab", []string{"invalid UTF-8 byte 0xff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := stripIllegalChars(tt.value)
+			if got != tt.want || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("stripIllegalChars() = %q, %v, want %q, %v", got, removed, tt.want, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestEncodeIllegalChars(t *testing.T) {
+	now := time.Date(2017, time.December, 21, 8, 30, 15, 0, time.UTC)
+	feedID := NewFeedID("example.com", now, "blog")
+	entry := NewEntry(NewEntryID(feedID, now), "Pasted", "https://example.com/pasted", nil, now, now, nil, nil, []byte("<p>Hello\x1b[0m World</p>"))
+	entry.Summary = &Content{Type: "xhtml", ValueXML: "<div xmlns=\"http://www.w3.org/1999/xhtml\">Hello\x0c World</div>"}
+	feed := NewFeed(feedID, NewPerson("Go\x08 Pher", "", ""), "Blog", "", "https://example.com/", "https://example.com/feed.atom", now, []Entry{entry})
+
+	changes := []string{}
+	out := &bytes.Buffer{}
+	if err := feed.EncodeWithOptions(out, EncodeOptions{Report: func(change string) { changes = append(changes, change) }}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<name>Go Pher</name>", "&lt;p&gt;Hello[0m World&lt;/p&gt;", "Hello World</div>"} {
+		if strings.Contains(out.String(), want) == false {
+			t.Errorf("EncodeWithOptions() output is missing %q\n\ngot:\n%v", want, out)
+		}
+	}
+	wantChanges := []string{
+		"feed: author: name: removed illegal characters U+0008",
+		"entry " + entry.ID.Value + ": summary: removed illegal characters U+000C",
+		"entry " + entry.ID.Value + ": content: removed illegal characters U+001B",
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("EncodeWithOptions() reported %q, want %q", changes, wantChanges)
+	}
+	if feed.Author.Name != "Go\x08 Pher" || strings.Contains(feed.Entries[0].Content.Value, "\x1b") == false {
+		t.Error("EncodeWithOptions() must not modify the feed")
+	}
+	if _, err := Decode(out); err != nil {
+		t.Errorf("Decode() of cleaned feed failed: %v", err)
+	}
+}
+
+func TestEncodeMalformedFragment(t *testing.T) {
+	feed := Feed{
+		Title: &TextConstruct{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml">Fish &amp; Chips</div>`},
+		Entries: []Entry{{
+			ID:      NewID("urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6"),
+			Content: &Content{Type: "xhtml", ValueXML: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Fish &nbsp; Chips</div>`},
+		}},
+	}
+	out := &bytes.Buffer{}
+	err := feed.Encode(out)
+	if err == nil || strings.HasPrefix(err.Error(), "entry urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6: content: malformed XML:") == false {
+		t.Errorf("Encode() error = %v, want malformed XML error of entry content", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Encode() wrote %q, want no output", out)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestEncodeWriteError(t *testing.T) {
+	feed := Feed{Title: &TextConstruct{Value: "Blog"}}
+	for _, o := range []EncodeOptions{{}, {SelfClosing: true}} {
+		if err := feed.EncodeWithOptions(failingWriter{}, o); err == nil || err.Error() != "disk full" {
+			t.Errorf("EncodeWithOptions(%+v) error = %v, want disk full", o, err)
+		}
+	}
+}